@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"volaticloud/internal/billing"
+)
+
+// billingCommand groups operator-triggerable billing maintenance jobs.
+func billingCommand(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "billing",
+		Usage: "Billing maintenance jobs",
+		Subcommands: []*cli.Command{
+			invoiceCommand(ctx),
+			verifyLedgerCommand(ctx),
+		},
+	}
+}
+
+var periodFlag = &cli.StringFlag{
+	Name:  "period",
+	Usage: "Billing period as YYYY-MM (default: previous calendar month)",
+}
+
+var stripeKeyFlag = &cli.StringFlag{
+	Name:    "stripe-key",
+	Usage:   "Stripe secret API key",
+	EnvVars: []string{"STRIPE_SECRET_KEY"},
+}
+
+// invoiceCommand exposes the three invoicing pipeline stages independently,
+// so an operator can inspect prepared records before anything reaches
+// Stripe, following the same prepare/items/finalize split as InvoiceService.
+func invoiceCommand(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "invoice",
+		Usage: "Generate monthly usage invoices from the credit ledger",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "prepare",
+				Usage: "Print the invoice records that would be generated for a period",
+				Flags: []cli.Flag{periodFlag},
+				Action: func(c *cli.Context) error {
+					return withInvoiceService(ctx, c, func(svc *billing.InvoiceService, start, end time.Time) error {
+						records, err := svc.PrepareInvoiceRecords(ctx, start, end)
+						if err != nil {
+							return err
+						}
+						for _, rec := range records {
+							fmt.Printf("%s ref=%s items=%d\n", rec.OwnerID, rec.ReferenceID, len(rec.Items))
+						}
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "items",
+				Usage: "Create pending Stripe invoice items for a period",
+				Flags: []cli.Flag{periodFlag, stripeKeyFlag},
+				Action: func(c *cli.Context) error {
+					return withInvoiceService(ctx, c, func(svc *billing.InvoiceService, start, end time.Time) error {
+						records, err := svc.PrepareInvoiceRecords(ctx, start, end)
+						if err != nil {
+							return err
+						}
+						return svc.CreateInvoiceItems(records)
+					})
+				},
+			},
+			{
+				Name:  "finalize",
+				Usage: "Finalize draft invoices and record the idempotent ledger entry",
+				Flags: []cli.Flag{periodFlag, stripeKeyFlag},
+				Action: func(c *cli.Context) error {
+					return withInvoiceService(ctx, c, func(svc *billing.InvoiceService, start, end time.Time) error {
+						records, err := svc.PrepareInvoiceRecords(ctx, start, end)
+						if err != nil {
+							return err
+						}
+						return svc.CreateInvoices(ctx, records)
+					})
+				},
+			},
+		},
+	}
+}
+
+// verifyLedgerCommand recomputes the hash chain for one org (or every org
+// with ledger activity, if --owner-id is omitted), checks every org before
+// returning, and exits non-zero if any of them has a tampered row.
+func verifyLedgerCommand(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "verify-ledger",
+		Usage: "Verify the credit transaction ledger's hash chain",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "owner-id",
+				Usage: "Verify only this organization's ledger (default: all organizations)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			client, err := openClient(ctx, c.String("database"))
+			if err != nil {
+				return err
+			}
+			defer func() { _ = client.Close() }()
+
+			ownerIDs := []string{c.String("owner-id")}
+			if ownerIDs[0] == "" {
+				ownerIDs, err = billing.LedgerOwnerIDs(ctx, client)
+				if err != nil {
+					return err
+				}
+			}
+
+			tampered := 0
+			for _, ownerID := range ownerIDs {
+				result, err := billing.VerifyLedger(ctx, client, ownerID)
+				if err != nil {
+					return fmt.Errorf("failed to verify ledger for %s: %w", ownerID, err)
+				}
+				if result == nil {
+					fmt.Printf("%s: ok\n", ownerID)
+					continue
+				}
+				tampered++
+				fmt.Printf("%s: TAMPERED at transaction %s: %s\n", ownerID, result.Transaction.ID, result.Reason)
+			}
+
+			if tampered > 0 {
+				return fmt.Errorf("%d organization(s) failed ledger verification", tampered)
+			}
+			return nil
+		},
+	}
+}
+
+// withInvoiceService opens the DB, resolves the requested billing period,
+// and runs fn with a ready-to-use InvoiceService.
+func withInvoiceService(ctx context.Context, c *cli.Context, fn func(svc *billing.InvoiceService, start, end time.Time) error) error {
+	client, err := openClient(ctx, c.String("database"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	start, end, err := resolvePeriod(c.String("period"))
+	if err != nil {
+		return err
+	}
+
+	stripeClient := billing.NewStripeClient(c.String("stripe-key"))
+	svc := billing.NewInvoiceService(client, stripeClient)
+	return fn(svc, start, end)
+}
+
+// resolvePeriod parses "YYYY-MM" into the [start, end) range for that
+// calendar month, defaulting to the previous calendar month when period is
+// empty.
+func resolvePeriod(period string) (start, end time.Time, err error) {
+	if period == "" {
+		start, end = billing.PreviousBillingPeriod(time.Now())
+		return start, end, nil
+	}
+
+	start, err = time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --period %q, expected YYYY-MM: %w", period, err)
+	}
+	end = start.AddDate(0, 1, 0)
+	return start, end, nil
+}
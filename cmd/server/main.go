@@ -26,6 +26,8 @@ import (
 	"volaticloud/internal/graph"
 	"volaticloud/internal/logger"
 	"volaticloud/internal/monitor"
+	"volaticloud/internal/runner"
+	"volaticloud/internal/usage"
 )
 
 func main() {
@@ -72,6 +74,10 @@ func main() {
 		Action: func(c *cli.Context) error {
 			return runServer(ctx, c)
 		},
+		Commands: []*cli.Command{
+			billingCommand(ctx),
+			secretsCommand(ctx),
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -108,6 +114,31 @@ func parseDatabase(dbURL string) (driver, dsn string, err error) {
 	return "", "", fmt.Errorf("unsupported database URL format: %s (use sqlite:// or postgresql://)", dbURL)
 }
 
+// openClient opens an ENT client for dbURL, applying auto-migration. Callers
+// must close the returned client.
+func openClient(ctx context.Context, dbURL string) (*ent.Client, error) {
+	driver, dsn, err := parseDatabase(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ent.Open(
+		driver,
+		dsn,
+		ent.Log(logger.EntAdapterFromContext(ctx)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening connection to %s: %w", driver, err)
+	}
+
+	if err := client.Schema.Create(ctx); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed creating schema resources: %w", err)
+	}
+
+	return client, nil
+}
+
 func runServer(parentCtx context.Context, c *cli.Context) error {
 	// Get logger from parent context
 	log := logger.GetLogger(parentCtx)
@@ -133,22 +164,12 @@ func runServer(parentCtx context.Context, c *cli.Context) error {
 		return err
 	}
 
-	// Initialize database connection with ZAP logger
-	client, err := ent.Open(
-		driver,
-		dsn,
-		ent.Log(logger.EntAdapterFromContext(ctx)),
-	)
+	client, err := openClient(ctx, dbURL)
 	if err != nil {
-		return fmt.Errorf("failed opening connection to %s: %w", driver, err)
+		return err
 	}
 	defer func() { _ = client.Close() }()
 
-	// Run auto migration
-	if err := client.Schema.Create(ctx); err != nil {
-		return fmt.Errorf("failed creating schema resources: %w", err)
-	}
-
 	host := c.String("host")
 	port := c.Int("port")
 
@@ -156,10 +177,16 @@ func runServer(parentCtx context.Context, c *cli.Context) error {
 	etcdEndpoints := c.StringSlice("etcd-endpoints")
 	monitorInterval := c.Duration("monitor-interval")
 
+	usageExporter := usage.NewPrometheusExporter()
+	if err := usageExporter.LoadFromAggregations(ctx, client); err != nil {
+		log.Warn("Failed to seed usage metrics from existing aggregations", zap.Error(err))
+	}
+
 	monitorManager, err := monitor.NewManager(monitor.Config{
-		DatabaseClient:  client,
-		EtcdEndpoints:   etcdEndpoints,
-		MonitorInterval: monitorInterval,
+		DatabaseClient:          client,
+		EtcdEndpoints:           etcdEndpoints,
+		MonitorInterval:         monitorInterval,
+		UsagePrometheusExporter: usageExporter,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create monitor manager: %w", err)
@@ -212,6 +239,13 @@ func runServer(parentCtx context.Context, c *cli.Context) error {
 		w.Write([]byte("OK"))
 	})
 
+	// Admin endpoint: runner config JSON Schemas, for the dashboard to render
+	// per-runner-type config forms.
+	router.Get("/admin/runner-schemas/{type}", runner.SchemaHandler().ServeHTTP)
+
+	// Usage billing metrics, in Prometheus text exposition format.
+	router.Handle("/metrics", usageExporter)
+
 	// HTTP server
 	addr := fmt.Sprintf("%s:%d", host, port)
 	httpServer := &http.Server{
@@ -0,0 +1,12 @@
+// Package analytics provides a small pluggable sink for operator-facing
+// events that don't fit the structured audit.Record model — reconciliation
+// flags, anomaly signals — where a log line or webhook ping is enough.
+//
+//	analytics.Init(analytics.NewLogNotifier())
+//	...
+//	analytics.Notify(ctx, analytics.Event{
+//		Type:    "package_credit_reconciliation_needed",
+//		OwnerID: ownerID,
+//		Message: "org still holds non-package credit after package expiry",
+//	})
+package analytics
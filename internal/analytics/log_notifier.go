@@ -0,0 +1,20 @@
+package analytics
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes events as a log line, matching the repo's [TAG] action=
+// convention used elsewhere (e.g. billing's "[BILLING] action=...").
+type LogNotifier struct{}
+
+// NewLogNotifier creates a LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, event Event) error {
+	log.Printf("[ANALYTICS] type=%s owner=%s message=%q", event.Type, event.OwnerID, event.Message)
+	return nil
+}
@@ -0,0 +1,48 @@
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a single operator-facing notification.
+type Event struct {
+	Type      string
+	OwnerID   string
+	Message   string
+	Data      map[string]interface{}
+	Timestamp time.Time
+}
+
+// Notifier delivers Events somewhere an operator will see them.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// DefaultNotifier is the singleton notifier initialized at startup, following
+// the same optional-singleton convention as secrets.DefaultEncryptor and
+// audit.DefaultAuditor: call sites emit through Notify and it is a no-op
+// until Init is called.
+var DefaultNotifier Notifier
+
+// Init sets DefaultNotifier. Pass nil to disable notifications (e.g. in tests).
+func Init(notifier Notifier) {
+	DefaultNotifier = notifier
+}
+
+// Enabled returns true if DefaultNotifier has been initialized.
+func Enabled() bool {
+	return DefaultNotifier != nil
+}
+
+// Notify sends event through DefaultNotifier if enabled, otherwise it is a
+// no-op so call sites don't need their own nil checks.
+func Notify(ctx context.Context, event Event) error {
+	if !Enabled() {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	return DefaultNotifier.Notify(ctx, event)
+}
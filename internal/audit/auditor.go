@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Auditor produces signed, append-only audit records. Implementations
+// decide where records land (Sink) and how the hash chain's starting point
+// is tracked.
+type Auditor interface {
+	// Record fills in ID/Timestamp/PrevHash/Hash on rec if unset and writes
+	// it to the configured sink.
+	Record(ctx context.Context, rec Record) error
+}
+
+// chainedAuditor hash-chains every record to the previous one in memory and
+// fans writes out to a Sink.
+type chainedAuditor struct {
+	mu       sync.Mutex
+	sink     Sink
+	lastHash string
+}
+
+// NewChainedAuditor builds an Auditor writing to sink. The chain starts from
+// the zero hash; pass a non-empty seed (e.g. the last hash read back from
+// durable storage at startup) via Seed to continue an existing chain across
+// process restarts.
+func NewChainedAuditor(sink Sink) *chainedAuditor {
+	return &chainedAuditor{sink: sink}
+}
+
+// Seed continues the hash chain from a previously observed hash, e.g. one
+// read back from storage at startup.
+func (a *chainedAuditor) Seed(lastHash string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastHash = lastHash
+}
+
+func (a *chainedAuditor) Record(ctx context.Context, rec Record) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if rec.ID == "" {
+		rec.ID = uuid.New().String()
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now().UTC()
+	}
+	rec.PrevHash = a.lastHash
+
+	hash, err := rec.computeHash()
+	if err != nil {
+		return err
+	}
+	rec.Hash = hash
+
+	if err := a.sink.Write(ctx, rec); err != nil {
+		return fmt.Errorf("audit: write record: %w", err)
+	}
+	a.lastHash = hash
+	return nil
+}
+
+// DefaultAuditor is the singleton auditor initialized at startup, following
+// the same optional-singleton convention as secrets.DefaultEncryptor: call
+// sites emit through Emit and it is a no-op until Init is called.
+var DefaultAuditor Auditor
+
+// Init sets DefaultAuditor. Pass nil to disable auditing (e.g. in tests).
+func Init(auditor Auditor) {
+	DefaultAuditor = auditor
+}
+
+// Enabled returns true if DefaultAuditor has been initialized.
+func Enabled() bool {
+	return DefaultAuditor != nil
+}
+
+// Emit records rec through DefaultAuditor if auditing is enabled, otherwise
+// it is a no-op. Call sites should prefer this over calling DefaultAuditor
+// directly so instrumentation doesn't need its own nil checks.
+func Emit(ctx context.Context, rec Record) error {
+	if !Enabled() {
+		return nil
+	}
+	return DefaultAuditor.Record(ctx, rec)
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Store reads back previously written records, ordered by Timestamp, for
+// chain verification and the auditLog query. FileSink implements it.
+type Store interface {
+	Range(ctx context.Context, from, to time.Time) ([]Record, error)
+}
+
+// ChainBreak describes the first record whose stored Hash no longer matches
+// its recomputed hash, or whose PrevHash doesn't match the prior record's
+// Hash — either indicates the record was altered or removed after the fact.
+type ChainBreak struct {
+	Record   Record
+	Expected string
+	Got      string
+}
+
+// VerifyChain walks records returned by store for [from, to), recomputing
+// the hash chain, and returns the first break found (nil if the chain is
+// intact). Verification must see every record, including ones a soft-delete
+// interceptor would otherwise hide — callers should pass a Store that reads
+// with mixin.IncludeDeleted applied.
+func VerifyChain(ctx context.Context, store Store, from, to time.Time) (*ChainBreak, error) {
+	records, err := store.Range(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("audit: range: %w", err)
+	}
+
+	// A range that starts after the chain's first record is anchored to its
+	// own first record's PrevHash rather than to the empty genesis hash.
+	prevHash := ""
+	if !from.IsZero() && len(records) > 0 {
+		prevHash = records[0].PrevHash
+	}
+	for _, rec := range records {
+		if rec.PrevHash != prevHash {
+			return &ChainBreak{Record: rec, Expected: prevHash, Got: rec.PrevHash}, nil
+		}
+
+		wantHash := rec.Hash
+		recomputed, err := rec.computeHash()
+		if err != nil {
+			return nil, err
+		}
+		if recomputed != wantHash {
+			return &ChainBreak{Record: rec, Expected: recomputed, Got: wantHash}, nil
+		}
+
+		prevHash = rec.Hash
+	}
+
+	return nil, nil
+}
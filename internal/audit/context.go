@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"volaticloud/internal/auth"
+)
+
+// ActorFromContext returns the JWT sub of the authenticated caller for use as
+// Record.Actor, or "" if ctx carries no user context (e.g. a
+// service/background call with no request-scoped caller).
+func ActorFromContext(ctx context.Context) string {
+	user, err := auth.GetUserContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return user.UserID
+}
+
+// RequestIDFromContext returns the inbound HTTP request ID chi's
+// middleware.RequestID stamped on ctx, for use as Record.RequestID, or "" if
+// ctx carries none (e.g. a call made outside a request, such as a CLI or
+// background job).
+func RequestIDFromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
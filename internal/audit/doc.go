@@ -0,0 +1,34 @@
+// Package audit provides a structured, hash-chained audit log for
+// permission changes, billing events, and secret access.
+//
+// # Architecture
+//
+// Auditor.Record builds a Record from the given fields, chains it to the
+// previous record's hash, and writes it to the auditor's Sink: StdoutSink
+// (JSONL), FileSink (a rotating JSONL file), or a MultiSink that fans out
+// to several of them. There is no Kafka or S3 sink. Because each
+// record embeds the SHA-256 hash of the one before it, tampering with or
+// deleting a historical record is detectable: VerifyChain recomputes the
+// chain from a Store and reports the first record whose stored hash no
+// longer matches.
+//
+// # Instrumentation
+//
+// Call sites emit audit records through the package-level DefaultAuditor,
+// mirroring the secrets.DefaultEncryptor convention — instrumentation is a
+// few lines at the call site, and audit.Enabled() lets it no-op when the
+// package hasn't been initialized (e.g. in unit tests):
+//
+//	audit.Init(audit.NewChainedAuditor(audit.NewStdoutSink(os.Stdout)))
+//	...
+//	audit.Emit(ctx, audit.Record{
+//		Actor:      audit.ActorFromContext(ctx),
+//		Action:     "billing.change_plan",
+//		TargetType: "StripeSubscription",
+//		TargetID:   sub.ID.String(),
+//	})
+//
+// # Verification
+//
+//	violation, err := audit.VerifyChain(ctx, store, from, to)
+package audit
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Range implements Store by re-reading the JSONL file FileSink wrote,
+// filtering to [from, to). It does not consider rotated backups.
+func (s *FileSink) Range(_ context.Context, from, to time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	var out []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("audit: decode record: %w", err)
+		}
+		if rec.Timestamp.Before(from) || !rec.Timestamp.Before(to) {
+			continue
+		}
+		out = append(out, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("audit: scan %s: %w", s.path, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LogFilter narrows an audit log query. Zero values are unrestricted.
+type LogFilter struct {
+	From       time.Time
+	To         time.Time
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+}
+
+// ErrForbidden is returned by QueryLog when the caller isn't authorized to
+// read the audit log.
+var ErrForbidden = fmt.Errorf("audit: admin scope required")
+
+// QueryLog returns records from store matching filter. isAdmin should come
+// from the caller's existing UMA/admin-scope check (see
+// graph.SyncResourceScopes for the resource-scope equivalent) — this
+// package intentionally has no Keycloak dependency of its own.
+func QueryLog(ctx context.Context, store Store, filter LogFilter, isAdmin bool) ([]Record, error) {
+	if !isAdmin {
+		return nil, ErrForbidden
+	}
+
+	from, to := filter.From, filter.To
+	if to.IsZero() {
+		to = time.Now().Add(time.Second) // inclusive of "now"
+	}
+
+	records, err := store.Range(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	out := records[:0]
+	for _, rec := range records {
+		if filter.Actor != "" && rec.Actor != filter.Actor {
+			continue
+		}
+		if filter.Action != "" && rec.Action != filter.Action {
+			continue
+		}
+		if filter.TargetType != "" && rec.TargetType != filter.TargetType {
+			continue
+		}
+		if filter.TargetID != "" && rec.TargetID != filter.TargetID {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Record is a single append-only audit entry. Hash is computed over the
+// canonical JSON of every other field plus PrevHash, so altering a record
+// or its position in the chain is detectable by VerifyChain.
+type Record struct {
+	ID            string    `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Actor         string    `json:"actor"`      // JWT sub of the caller
+	Action        string    `json:"action"`     // e.g. "billing.change_plan"
+	TargetType    string    `json:"targetType"` // e.g. "StripeSubscription"
+	TargetID      string    `json:"targetId"`
+	BeforeHash    string    `json:"beforeHash,omitempty"` // SHA-256 of pre-change state, if known
+	AfterHash     string    `json:"afterHash,omitempty"`  // SHA-256 of post-change state, if known
+	RequestID     string    `json:"requestId,omitempty"`
+	CorrelationID string    `json:"correlationId,omitempty"` // Stripe/Keycloak correlation ID
+	PrevHash      string    `json:"prevHash"`
+	Hash          string    `json:"hash"`
+}
+
+// canonicalPayload returns the subset of fields that go into Hash, excluding
+// Hash itself.
+func (r Record) canonicalPayload() ([]byte, error) {
+	return json.Marshal(struct {
+		ID            string    `json:"id"`
+		Timestamp     time.Time `json:"timestamp"`
+		Actor         string    `json:"actor"`
+		Action        string    `json:"action"`
+		TargetType    string    `json:"targetType"`
+		TargetID      string    `json:"targetId"`
+		BeforeHash    string    `json:"beforeHash,omitempty"`
+		AfterHash     string    `json:"afterHash,omitempty"`
+		RequestID     string    `json:"requestId,omitempty"`
+		CorrelationID string    `json:"correlationId,omitempty"`
+		PrevHash      string    `json:"prevHash"`
+	}{
+		ID:            r.ID,
+		Timestamp:     r.Timestamp,
+		Actor:         r.Actor,
+		Action:        r.Action,
+		TargetType:    r.TargetType,
+		TargetID:      r.TargetID,
+		BeforeHash:    r.BeforeHash,
+		AfterHash:     r.AfterHash,
+		RequestID:     r.RequestID,
+		CorrelationID: r.CorrelationID,
+		PrevHash:      r.PrevHash,
+	})
+}
+
+// computeHash returns the hex-encoded SHA-256 of r's canonical payload.
+func (r Record) computeHash() (string, error) {
+	payload, err := r.canonicalPayload()
+	if err != nil {
+		return "", fmt.Errorf("audit: marshal record: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashState returns the hex-encoded SHA-256 of an arbitrary before/after
+// state value, for use as Record.BeforeHash/AfterHash.
+func HashState(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("audit: marshal state: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
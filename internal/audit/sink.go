@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink persists a single audit Record. Implementations must not mutate rec.
+type Sink interface {
+	Write(ctx context.Context, rec Record) error
+}
+
+// StdoutSink writes one JSON object per line to w (conventionally os.Stdout),
+// suitable for log-aggregation pipelines.
+type StdoutSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutSink builds a StdoutSink writing to w.
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+func (s *StdoutSink) Write(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(s.w)
+	return enc.Encode(rec)
+}
+
+// FileSink appends JSONL records to a file, rotating to a numbered backup
+// once the active file exceeds MaxBytes. It also implements Store so
+// VerifyChain can read back what it wrote.
+type FileSink struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	f        *os.File
+}
+
+// NewFileSink opens (or creates) path for appending, rotating once it grows
+// past maxBytes. maxBytes <= 0 disables rotation.
+func NewFileSink(path string, maxBytes int64) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open %s: %w", path, err)
+	}
+	return &FileSink{path: path, maxBytes: maxBytes, f: f}, nil
+}
+
+func (s *FileSink) Write(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rotateIfNeeded(); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("audit: marshal record: %w", err)
+	}
+	if _, err := s.f.Write(append(raw, '\n')); err != nil {
+		return fmt.Errorf("audit: write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *FileSink) rotateIfNeeded() error {
+	if s.maxBytes <= 0 {
+		return nil
+	}
+	info, err := s.f.Stat()
+	if err != nil {
+		return fmt.Errorf("audit: stat %s: %w", s.path, err)
+	}
+	if info.Size() < s.maxBytes {
+		return nil
+	}
+
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("audit: close %s: %w", s.path, err)
+	}
+	backup := s.path + ".1"
+	if err := os.Rename(s.path, backup); err != nil {
+		return fmt.Errorf("audit: rotate %s: %w", s.path, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit: reopen %s: %w", s.path, err)
+	}
+	s.f = f
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// MultiSink fans a record out to every sink in order, returning the first
+// error encountered but still attempting the rest so one unavailable
+// backend (e.g. Kafka) doesn't silently drop the record everywhere else.
+type MultiSink []Sink
+
+func (m MultiSink) Write(ctx context.Context, rec Record) error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Write(ctx, rec); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"volaticloud/internal/db"
@@ -77,7 +78,7 @@ func AddCredits(ctx context.Context, client *ent.Client, ownerID string, amount
 	var updatedBal *ent.CreditBalance
 
 	err := db.WithTx(ctx, client, func(tx *ent.Tx) error {
-		return addCreditsInTx(ctx, tx, ownerID, amount, txType, description, referenceID, &updatedBal)
+		return addCreditsInTx(ctx, tx, ownerID, amount, txType, description, referenceID, nil, "", &updatedBal)
 	})
 	if err != nil {
 		return nil, err
@@ -86,7 +87,48 @@ func AddCredits(ctx context.Context, client *ent.Client, ownerID string, amount
 	return updatedBal, nil
 }
 
-func addCreditsInTx(ctx context.Context, tx *ent.Tx, ownerID string, amount float64, txType enum.CreditTransactionType, description string, referenceID string, result **ent.CreditBalance) error {
+// AddPackageCredits records a promo/package credit deposit that expires at
+// expiresAt. Unlike AddCredits, these funds are tracked as a lot (via
+// remaining_amount) so DeductCredits can consume them FIFO by expiry ahead
+// of non-expiring credit, and ExpirePackageCredits can claw back whatever is
+// left unused once expiresAt passes.
+func AddPackageCredits(ctx context.Context, client *ent.Client, ownerID string, amount float64, expiresAt time.Time, packageID string) (*ent.CreditBalance, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("package credit amount must be positive, got %f", amount)
+	}
+	if packageID == "" {
+		return nil, fmt.Errorf("package credit requires a packageID")
+	}
+
+	referenceID := fmt.Sprintf("package:%s:%s", ownerID, packageID)
+	exists, err := client.CreditTransaction.Query().
+		Where(credittransaction.ReferenceID(referenceID)).
+		Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+	if exists {
+		log.Printf("[BILLING] action=add_package_credits_skip owner=%s package=%s reason=duplicate", ownerID, packageID)
+		return GetBalance(ctx, client, ownerID)
+	}
+
+	if err := EnsureBalanceExists(ctx, client, ownerID); err != nil {
+		return nil, err
+	}
+
+	var updatedBal *ent.CreditBalance
+	err = db.WithTx(ctx, client, func(tx *ent.Tx) error {
+		description := fmt.Sprintf("Package credit (%s)", packageID)
+		return addCreditsInTx(ctx, tx, ownerID, amount, enum.CreditTxPackageCredit, description, referenceID, &expiresAt, packageID, &updatedBal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedBal, nil
+}
+
+func addCreditsInTx(ctx context.Context, tx *ent.Tx, ownerID string, amount float64, txType enum.CreditTransactionType, description string, referenceID string, expiresAt *time.Time, packageID string, result **ent.CreditBalance) error {
 	// Re-check idempotency inside tx
 	if referenceID != "" {
 		exists, err := tx.CreditTransaction.Query().
@@ -132,16 +174,24 @@ func addCreditsInTx(ctx context.Context, tx *ent.Tx, ownerID string, amount floa
 		return fmt.Errorf("failed to update balance: %w", err)
 	}
 
-	// Record transaction
+	// Record transaction. remaining_amount starts equal to amount so
+	// DeductCredits can track how much of this lot is still unconsumed.
 	txCreate := tx.CreditTransaction.Create().
 		SetOwnerID(ownerID).
 		SetAmount(amount).
 		SetBalanceAfter(newBalance).
 		SetType(txType).
-		SetDescription(description)
+		SetDescription(description).
+		SetRemainingAmount(amount)
 	if referenceID != "" {
 		txCreate = txCreate.SetReferenceID(referenceID)
 	}
+	if expiresAt != nil {
+		txCreate = txCreate.SetExpiresAt(*expiresAt)
+	}
+	if packageID != "" {
+		txCreate = txCreate.SetPackageID(packageID)
+	}
 	if _, err := txCreate.Save(ctx); err != nil {
 		return fmt.Errorf("failed to record credit transaction: %w", err)
 	}
@@ -235,6 +285,10 @@ func deductCreditsInTx(ctx context.Context, tx *ent.Tx, ownerID string, amount f
 		return fmt.Errorf("failed to update balance: %w", err)
 	}
 
+	if err := consumeLotsFIFO(ctx, tx, ownerID, amount); err != nil {
+		return fmt.Errorf("failed to consume credit lots: %w", err)
+	}
+
 	// Record transaction
 	txCreate := tx.CreditTransaction.Create().
 		SetOwnerID(ownerID).
@@ -252,3 +306,58 @@ func deductCreditsInTx(ctx context.Context, tx *ent.Tx, ownerID string, amount f
 	*result = updatedBal
 	return nil
 }
+
+// consumeLotsFIFO decrements remaining_amount on ownerID's credit-granting
+// transactions to account for a deduction of amount, preferring the
+// soonest-expiring lot first so promo/package credit is used up before it
+// expires. Lots with no expiry are treated as expiring last. If amount
+// exceeds the sum of tracked lots (e.g. older data with no remaining_amount),
+// the excess is left unconsumed — CreditBalance.Balance remains the source
+// of truth for the org's total, this only tracks per-lot attribution.
+func consumeLotsFIFO(ctx context.Context, tx *ent.Tx, ownerID string, amount float64) error {
+	lots, err := tx.CreditTransaction.Query().
+		Where(
+			credittransaction.OwnerID(ownerID),
+			credittransaction.RemainingAmountGT(0),
+		).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query credit lots: %w", err)
+	}
+
+	sort.Slice(lots, func(i, j int) bool {
+		ei, ej := lots[i].ExpiresAt, lots[j].ExpiresAt
+		switch {
+		case ei == nil && ej == nil:
+			return lots[i].CreatedAt.Before(lots[j].CreatedAt)
+		case ei == nil:
+			return false
+		case ej == nil:
+			return true
+		case !ei.Equal(*ej):
+			return ei.Before(*ej)
+		default:
+			return lots[i].CreatedAt.Before(lots[j].CreatedAt)
+		}
+	})
+
+	remaining := amount
+	for _, lot := range lots {
+		if remaining <= 0 {
+			break
+		}
+		available := *lot.RemainingAmount
+		consumed := available
+		if consumed > remaining {
+			consumed = remaining
+		}
+		if _, err := tx.CreditTransaction.UpdateOneID(lot.ID).
+			SetRemainingAmount(available - consumed).
+			Save(ctx); err != nil {
+			return fmt.Errorf("failed to update lot %s: %w", lot.ID, err)
+		}
+		remaining -= consumed
+	}
+
+	return nil
+}
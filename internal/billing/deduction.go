@@ -6,6 +6,8 @@ import (
 	"log"
 	"time"
 
+	"github.com/google/uuid"
+
 	"volaticloud/internal/ent"
 	"volaticloud/internal/ent/resourceusageaggregation"
 	"volaticloud/internal/enum"
@@ -47,13 +49,6 @@ func (s *BillingService) DeductHourlyCosts(ctx context.Context, bucketStart time
 	// Group aggregations by owner_id and calculate total cost per org
 	ownerCosts := make(map[string]float64)
 	for _, agg := range aggregations {
-		// Get runner rates for this aggregation
-		rates, err := s.calculator.GetRunnerRates(ctx, agg.RunnerID)
-		if err != nil {
-			log.Printf("[BILLING] action=get_runner_rates_fail runner=%s error=%v", agg.RunnerID, err)
-			continue
-		}
-
 		summary := &usage.UsageSummary{
 			CPUCoreSeconds:  agg.CPUCoreSeconds,
 			MemoryGBSeconds: agg.MemoryGBSeconds,
@@ -63,9 +58,13 @@ func (s *BillingService) DeductHourlyCosts(ctx context.Context, bucketStart time
 			BlockWriteBytes: agg.BlockWriteBytes,
 		}
 
-		cost := s.calculator.CalculateCost(summary, rates)
-		if cost != nil && cost.TotalCost > 0 {
-			ownerCosts[agg.OwnerID] += cost.TotalCost
+		totalCost, err := s.priceAggregation(ctx, agg.RunnerID, summary)
+		if err != nil {
+			log.Printf("[BILLING] action=price_aggregation_fail runner=%s error=%v", agg.RunnerID, err)
+			continue
+		}
+		if totalCost > 0 {
+			ownerCosts[agg.OwnerID] += totalCost
 		}
 	}
 
@@ -94,3 +93,30 @@ func (s *BillingService) DeductHourlyCosts(ctx context.Context, bucketStart time
 
 	return nil
 }
+
+// priceAggregation prices one runner's usage summary, preferring the
+// runner's tiered PricingPlan over its flat per-unit rates when one is
+// configured. Each call prices a single hourly aggregation on its own, so a
+// plan's free tiers and committed-use hours apply per aggregation; the
+// summary DeductHourlyCosts builds carries no period or sample count, so a
+// plan's sustained-use discount is never applied here.
+func (s *BillingService) priceAggregation(ctx context.Context, runnerID uuid.UUID, summary *usage.UsageSummary) (float64, error) {
+	plan, err := s.calculator.GetRunnerPricingPlan(ctx, runnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pricing plan: %w", err)
+	}
+	if plan != nil {
+		priced := usage.NewPricingEngine().Calculate(summary, plan)
+		return priced.TotalCost, nil
+	}
+
+	rates, err := s.calculator.GetRunnerRates(ctx, runnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get runner rates: %w", err)
+	}
+	cost := s.calculator.CalculateCost(summary, rates)
+	if cost == nil {
+		return 0, nil
+	}
+	return cost.TotalCost, nil
+}
@@ -18,8 +18,9 @@ import (
 
 // mockCalculator implements usage.Calculator for testing.
 type mockCalculator struct {
-	getRunnerRatesFn func(ctx context.Context, runnerID uuid.UUID) (*usage.RunnerRates, error)
-	calculateCostFn  func(summary *usage.UsageSummary, rates *usage.RunnerRates) *usage.UsageCost
+	getRunnerRatesFn       func(ctx context.Context, runnerID uuid.UUID) (*usage.RunnerRates, error)
+	calculateCostFn        func(summary *usage.UsageSummary, rates *usage.RunnerRates) *usage.UsageCost
+	getRunnerPricingPlanFn func(ctx context.Context, runnerID uuid.UUID) (*usage.PricingPlan, error)
 }
 
 func (m *mockCalculator) GetResourceUsage(ctx context.Context, resourceType enum.ResourceType, resourceID uuid.UUID, start, end time.Time) (*usage.UsageSummary, error) {
@@ -48,6 +49,13 @@ func (m *mockCalculator) GetRunnerRates(ctx context.Context, runnerID uuid.UUID)
 	return &usage.RunnerRates{}, nil
 }
 
+func (m *mockCalculator) GetRunnerPricingPlan(ctx context.Context, runnerID uuid.UUID) (*usage.PricingPlan, error) {
+	if m.getRunnerPricingPlanFn != nil {
+		return m.getRunnerPricingPlanFn(ctx, runnerID)
+	}
+	return nil, nil
+}
+
 func TestDeductHourlyCosts(t *testing.T) {
 	client := enttest.Open(t, "sqlite3", "file:billing_deduct_hourly?mode=memory&cache=shared&_fk=1")
 	defer client.Close()
@@ -231,6 +239,53 @@ func TestDeductHourlyCosts(t *testing.T) {
 		assert.True(t, bal.Suspended)
 	})
 
+	t.Run("runner with a pricing plan is priced by PricingEngine instead of flat rates", func(t *testing.T) {
+		bucketPlan := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+
+		require.NoError(t, EnsureBalanceExists(ctx, client, "org-plan"))
+		_, err := AddCredits(ctx, client, "org-plan", 100.0, enum.CreditTxManualDeposit, "fund", "fund-plan")
+		require.NoError(t, err)
+
+		_, err = client.ResourceUsageAggregation.Create().
+			SetResourceType(enum.ResourceTypeBot).
+			SetResourceID(uuid.New()).
+			SetOwnerID("org-plan").
+			SetRunnerID(runnerID).
+			SetGranularity(enum.AggregationGranularityHourly).
+			SetBucketStart(bucketPlan).
+			SetBucketEnd(bucketPlan.Add(time.Hour)).
+			SetCPUCoreSeconds(2 * usage.SecondsPerHour).
+			SetSampleCount(60).
+			Save(ctx)
+		require.NoError(t, err)
+
+		plan := &usage.PricingPlan{
+			Name: "tiered",
+			CPUTiers: []usage.PricingTier{
+				{UpToUnits: 1, PricePerUnit: 0.10},
+				{UpToUnits: 0, PricePerUnit: 0.05},
+			},
+		}
+		calc := &mockCalculator{
+			getRunnerPricingPlanFn: func(ctx context.Context, id uuid.UUID) (*usage.PricingPlan, error) {
+				return plan, nil
+			},
+			getRunnerRatesFn: func(ctx context.Context, id uuid.UUID) (*usage.RunnerRates, error) {
+				t.Fatal("flat rates should not be consulted when a pricing plan is configured")
+				return nil, nil
+			},
+		}
+
+		svc := NewBillingService(client, calc)
+		err = svc.DeductHourlyCosts(ctx, bucketPlan)
+		require.NoError(t, err)
+
+		bal, err := GetBalance(ctx, client, "org-plan")
+		require.NoError(t, err)
+		// 1 core-hour @ $0.10 + 1 core-hour @ $0.05 = $0.15
+		assert.InDelta(t, 100.0-0.15, bal.Balance, 0.001)
+	})
+
 	t.Run("zero cost means no deduction", func(t *testing.T) {
 		bucket3 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
 
@@ -6,6 +6,7 @@ import (
 
 	"volaticloud/internal/ent"
 	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/enum"
 )
 
 // EnsureSufficientCredits checks that an organization is not suspended and has credits.
@@ -29,6 +30,10 @@ func EnsureSufficientCredits(ctx context.Context, client *ent.Client, ownerID st
 		return fmt.Errorf("failed to check credit balance: %w", err)
 	}
 
+	if bal.LifecycleStatus == enum.OrgStatusPendingDeletion {
+		return ErrOrgPendingDeletion
+	}
+
 	if bal.Suspended {
 		return fmt.Errorf("organization suspended: insufficient credits. Please add credits to continue")
 	}
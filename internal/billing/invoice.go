@@ -0,0 +1,244 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"volaticloud/internal/db"
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/ent/credittransaction"
+	"volaticloud/internal/ent/stripesubscription"
+	"volaticloud/internal/enum"
+)
+
+// InvoiceLineItem is one usage-category total to be billed on an org's invoice.
+type InvoiceLineItem struct {
+	Category    enum.CreditTransactionType
+	AmountCents int64
+	Description string
+}
+
+// InvoiceRecord is one org's prepared invoice for a billing period, produced
+// by PrepareInvoiceRecords and consumed by CreateInvoiceItems/CreateInvoices.
+type InvoiceRecord struct {
+	OwnerID     string
+	CustomerID  string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	ReferenceID string
+	Items       []InvoiceLineItem
+}
+
+// InvoiceService generates monthly usage invoices from the credit ledger.
+// It mirrors Storj's prepare-invoice-records -> create-invoice-items ->
+// create-invoices pipeline so operators can run each stage independently
+// (e.g. to inspect prepared records before anything reaches Stripe).
+type InvoiceService struct {
+	client *ent.Client
+	stripe *StripeClient
+}
+
+// NewInvoiceService creates an InvoiceService.
+func NewInvoiceService(client *ent.Client, stripeClient *StripeClient) *InvoiceService {
+	return &InvoiceService{client: client, stripe: stripeClient}
+}
+
+// PreviousBillingPeriod returns the [start, end) range of the calendar month
+// before now, e.g. for any time in March it returns [Feb 1, Mar 1).
+func PreviousBillingPeriod(now time.Time) (start, end time.Time) {
+	firstOfThisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.UTC().Location())
+	end = firstOfThisMonth
+	start = end.AddDate(0, -1, 0)
+	return start, end
+}
+
+// invoiceReferenceID builds the idempotency key used both as the Stripe
+// invoice item description tag and the synthetic ledger entry's reference_id.
+func invoiceReferenceID(ownerID string, periodStart time.Time) string {
+	return fmt.Sprintf("invoice:%s:%s", ownerID, periodStart.Format("200601"))
+}
+
+// PrepareInvoiceRecords aggregates each org's usage_deduction transactions
+// for [start, end) into one InvoiceRecord per org, grouped by transaction
+// type. Orgs with a canceled subscription, no subscription at all, or
+// already carrying an invoice_generated entry for this period are skipped.
+func (s *InvoiceService) PrepareInvoiceRecords(ctx context.Context, start, end time.Time) ([]InvoiceRecord, error) {
+	deductions, err := s.client.CreditTransaction.Query().
+		Where(
+			credittransaction.TypeEQ(enum.CreditTxUsageDeduction),
+			credittransaction.CreatedAtGTE(start),
+			credittransaction.CreatedAtLT(end),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage deductions: %w", err)
+	}
+
+	totals := make(map[string]float64)
+	for _, tx := range deductions {
+		totals[tx.OwnerID] += -tx.Amount // deductions are stored negative; invoice in positive cents
+	}
+
+	records := make([]InvoiceRecord, 0, len(totals))
+	for ownerID, total := range totals {
+		if total <= 0 {
+			continue
+		}
+
+		referenceID := invoiceReferenceID(ownerID, start)
+		exists, err := s.client.CreditTransaction.Query().
+			Where(credittransaction.ReferenceID(referenceID)).
+			Exist(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check invoice idempotency for %s: %w", ownerID, err)
+		}
+		if exists {
+			log.Printf("[BILLING] action=invoice_skip owner=%s period=%s reason=already_invoiced", ownerID, start.Format("2006-01"))
+			continue
+		}
+
+		sub, err := s.client.StripeSubscription.Query().
+			Where(stripesubscription.OwnerID(ownerID)).
+			Only(ctx)
+		if err != nil {
+			log.Printf("[BILLING] action=invoice_skip owner=%s period=%s reason=no_subscription", ownerID, start.Format("2006-01"))
+			continue
+		}
+		if sub.Status == enum.StripeSubCanceled {
+			log.Printf("[BILLING] action=invoice_skip owner=%s period=%s reason=subscription_canceled", ownerID, start.Format("2006-01"))
+			continue
+		}
+		if isPendingDeletion(ctx, s.client, ownerID) {
+			log.Printf("[BILLING] action=invoice_skip owner=%s period=%s reason=pending_deletion", ownerID, start.Format("2006-01"))
+			continue
+		}
+
+		records = append(records, InvoiceRecord{
+			OwnerID:     ownerID,
+			CustomerID:  sub.StripeCustomerID,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			ReferenceID: referenceID,
+			Items: []InvoiceLineItem{
+				{
+					Category:    enum.CreditTxUsageDeduction,
+					AmountCents: int64(total * 100),
+					Description: fmt.Sprintf("Usage for %s", start.Format("2006-01")),
+				},
+			},
+		})
+	}
+
+	return records, nil
+}
+
+// CreateInvoiceItems pushes each record's line items to Stripe as pending
+// invoice items on the org's customer. Not safe to re-run: Stripe invoice items
+// have no idempotency key here, so callers should only invoke this once per
+// record (CreateInvoices' idempotency check only guards the ledger entry,
+// not these Stripe-side items).
+func (s *InvoiceService) CreateInvoiceItems(records []InvoiceRecord) error {
+	for _, rec := range records {
+		for _, item := range rec.Items {
+			if _, err := s.stripe.CreateInvoiceItem(rec.CustomerID, item.AmountCents, item.Description); err != nil {
+				return fmt.Errorf("failed to create invoice item for %s: %w", rec.OwnerID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CreateInvoices finalizes a draft invoice per record (pulling in the
+// pending items created by CreateInvoiceItems) and records a synthetic
+// zero-amount ledger entry under ReferenceID so re-running the pipeline for
+// the same period is a no-op once this stage has completed.
+func (s *InvoiceService) CreateInvoices(ctx context.Context, records []InvoiceRecord) error {
+	for _, rec := range records {
+		draft, err := s.stripe.CreateDraftInvoice(rec.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to create draft invoice for %s: %w", rec.OwnerID, err)
+		}
+
+		finalized, err := s.stripe.FinalizeInvoice(draft.ID)
+		if err != nil {
+			return fmt.Errorf("failed to finalize invoice for %s: %w", rec.OwnerID, err)
+		}
+
+		description := fmt.Sprintf("Invoice %s generated for %s", finalized.ID, rec.PeriodStart.Format("2006-01"))
+		if err := recordInvoiceGenerated(ctx, s.client, rec.OwnerID, rec.ReferenceID, description); err != nil {
+			return fmt.Errorf("failed to record invoice ledger entry for %s: %w", rec.OwnerID, err)
+		}
+
+		log.Printf("[BILLING] action=invoice_created owner=%s invoice=%s period=%s", rec.OwnerID, finalized.ID, rec.PeriodStart.Format("2006-01"))
+	}
+	return nil
+}
+
+// RunInvoicingPipeline runs all three stages back to back for [start, end).
+// Operators needing to inspect prepared records before anything reaches
+// Stripe should call the stages individually instead.
+func (s *InvoiceService) RunInvoicingPipeline(ctx context.Context, start, end time.Time) error {
+	records, err := s.PrepareInvoiceRecords(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if err := s.CreateInvoiceItems(records); err != nil {
+		return err
+	}
+	return s.CreateInvoices(ctx, records)
+}
+
+// recordInvoiceGenerated writes a zero-amount, balance-preserving
+// CreditTransaction marking that an invoice was generated for referenceID.
+// It exists alongside AddCredits/DeductCredits (which both reject
+// non-positive amounts) because this entry intentionally carries no balance
+// change — only an idempotency marker.
+func recordInvoiceGenerated(ctx context.Context, client *ent.Client, ownerID, referenceID, description string) error {
+	exists, err := client.CreditTransaction.Query().
+		Where(credittransaction.ReferenceID(referenceID)).
+		Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check idempotency: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	return db.WithTx(ctx, client, func(tx *ent.Tx) error {
+		exists, err := tx.CreditTransaction.Query().
+			Where(credittransaction.ReferenceID(referenceID)).
+			Exist(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if exists {
+			return nil
+		}
+
+		bal, err := tx.CreditBalance.Query().
+			Where(creditbalance.OwnerID(ownerID)).
+			Only(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get credit balance: %w", err)
+		}
+
+		_, err = tx.CreditTransaction.Create().
+			SetOwnerID(ownerID).
+			SetAmount(0).
+			SetBalanceAfter(bal.Balance).
+			SetType(enum.CreditTxInvoiceGenerated).
+			SetDescription(description).
+			SetReferenceID(referenceID).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to record invoice ledger entry: %w", err)
+		}
+		return nil
+	})
+}
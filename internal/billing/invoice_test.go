@@ -0,0 +1,121 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/ent/enttest"
+	"volaticloud/internal/enum"
+)
+
+func TestPreviousBillingPeriod(t *testing.T) {
+	start, end := PreviousBillingPeriod(time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestInvoiceService_PrepareInvoiceRecords(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:billing_invoice?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	periodStart := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	newSub := func(ownerID string, status enum.StripeSubStatus) {
+		_, err := client.StripeSubscription.Create().
+			SetOwnerID(ownerID).
+			SetStripeCustomerID("cus_" + ownerID).
+			SetStripeSubscriptionID("sub_" + ownerID).
+			SetStripePriceID("price_test").
+			SetPlanName("pro").
+			SetMonthlyDeposit(100).
+			SetStatus(status).
+			SetCurrentPeriodStart(periodStart).
+			SetCurrentPeriodEnd(periodEnd).
+			Save(ctx)
+		require.NoError(t, err)
+	}
+
+	newDeduction := func(ownerID string, amount float64, when time.Time) {
+		require.NoError(t, EnsureBalanceExists(ctx, client, ownerID))
+		_, err := client.CreditTransaction.Create().
+			SetOwnerID(ownerID).
+			SetAmount(-amount).
+			SetBalanceAfter(0).
+			SetType(enum.CreditTxUsageDeduction).
+			SetDescription("usage").
+			SetCreatedAt(when).
+			Save(ctx)
+		require.NoError(t, err)
+	}
+
+	newSub("org-active", enum.StripeSubActive)
+	newDeduction("org-active", 12.34, periodStart.Add(24*time.Hour))
+	newDeduction("org-active", 7.66, periodStart.Add(48*time.Hour))
+
+	newSub("org-canceled", enum.StripeSubCanceled)
+	newDeduction("org-canceled", 50, periodStart.Add(time.Hour))
+
+	newDeduction("org-no-sub", 5, periodStart.Add(time.Hour))
+
+	newSub("org-out-of-range", enum.StripeSubActive)
+	newDeduction("org-out-of-range", 9, periodStart.Add(-time.Hour))
+
+	svc := NewInvoiceService(client, nil)
+	records, err := svc.PrepareInvoiceRecords(ctx, periodStart, periodEnd)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	rec := records[0]
+	assert.Equal(t, "org-active", rec.OwnerID)
+	assert.Equal(t, "cus_org-active", rec.CustomerID)
+	assert.Equal(t, "invoice:org-active:202502", rec.ReferenceID)
+	require.Len(t, rec.Items, 1)
+	assert.Equal(t, int64(2000), rec.Items[0].AmountCents)
+}
+
+func TestInvoiceService_PrepareInvoiceRecords_SkipsAlreadyInvoicedOrg(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:billing_invoice_idempotent?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	periodStart := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.StripeSubscription.Create().
+		SetOwnerID("org-done").
+		SetStripeCustomerID("cus_org-done").
+		SetStripeSubscriptionID("sub_org-done").
+		SetStripePriceID("price_test").
+		SetPlanName("pro").
+		SetMonthlyDeposit(100).
+		SetStatus(enum.StripeSubActive).
+		SetCurrentPeriodStart(periodStart).
+		SetCurrentPeriodEnd(periodEnd).
+		Save(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, EnsureBalanceExists(ctx, client, "org-done"))
+	_, err = client.CreditTransaction.Create().
+		SetOwnerID("org-done").
+		SetAmount(-10).
+		SetBalanceAfter(0).
+		SetType(enum.CreditTxUsageDeduction).
+		SetDescription("usage").
+		SetCreatedAt(periodStart.Add(time.Hour)).
+		Save(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, recordInvoiceGenerated(ctx, client, "org-done", invoiceReferenceID("org-done", periodStart), "already invoiced"))
+
+	svc := NewInvoiceService(client, nil)
+	records, err := svc.PrepareInvoiceRecords(ctx, periodStart, periodEnd)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
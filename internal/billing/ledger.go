@@ -0,0 +1,83 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	"volaticloud/internal/db"
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/credittransaction"
+	"volaticloud/internal/ent/mixin"
+)
+
+// TamperedTransaction describes the first CreditTransaction row VerifyLedger
+// found whose stored hash doesn't match the recomputed chain.
+type TamperedTransaction struct {
+	Transaction *ent.CreditTransaction
+	Reason      string
+}
+
+// VerifyLedger recomputes ownerID's CreditTransaction hash chain, ordered by
+// created_at/id, and returns the first row that breaks it (nil if the chain
+// is intact). It runs with mixin.IncludeDeleted so a soft-deleted ledger row
+// — should CreditTransaction ever gain the SoftDeleteMixin — can't be used
+// to hide tampering from verification.
+func VerifyLedger(ctx context.Context, client *ent.Client, ownerID string) (*TamperedTransaction, error) {
+	ctx = mixin.IncludeDeleted(ctx)
+
+	txs, err := client.CreditTransaction.Query().
+		Where(credittransaction.OwnerID(ownerID)).
+		Order(ent.Asc(credittransaction.FieldCreatedAt), ent.Asc(credittransaction.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ledger for %s: %w", ownerID, err)
+	}
+
+	prevHash := ""
+	for _, tx := range txs {
+		if tx.PrevHash != prevHash {
+			return &TamperedTransaction{
+				Transaction: tx,
+				Reason:      "prev_hash does not match the preceding row's hash",
+			}, nil
+		}
+
+		wantHash, err := db.HashLedgerEntry(db.LedgerHashInput{
+			OwnerID:       tx.OwnerID,
+			Amount:        tx.Amount,
+			BalanceAfter:  tx.BalanceAfter,
+			Type:          string(tx.Type),
+			Description:   tx.Description,
+			ReferenceID:   tx.ReferenceID,
+			PackageID:     tx.PackageID,
+			CreatedAtUnix: tx.CreatedAt.Unix(),
+		}, prevHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recompute hash for %s: %w", tx.ID, err)
+		}
+
+		if tx.Hash != wantHash {
+			return &TamperedTransaction{
+				Transaction: tx,
+				Reason:      "stored hash does not match recomputed hash",
+			}, nil
+		}
+
+		prevHash = tx.Hash
+	}
+
+	return nil, nil
+}
+
+// LedgerOwnerIDs returns the distinct owner_id values with at least one
+// CreditTransaction, for callers (like the verify-ledger CLI) that need to
+// walk every org's ledger.
+func LedgerOwnerIDs(ctx context.Context, client *ent.Client) ([]string, error) {
+	ownerIDs, err := client.CreditTransaction.Query().
+		GroupBy(credittransaction.FieldOwnerID).
+		Strings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger owners: %w", err)
+	}
+	return ownerIDs, nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/stripe/stripe-go/v82"
+
+	"volaticloud/internal/audit"
 	"volaticloud/internal/ent"
 	"volaticloud/internal/ent/stripesubscription"
 	"volaticloud/internal/enum"
@@ -51,9 +54,41 @@ func ChangeSubscriptionPlan(ctx context.Context, client *ent.Client, stripeClien
 	}
 
 	log.Printf("[BILLING] action=plan_change owner=%s plan=%s deposit=%.2f", ownerID, planName, monthlyDeposit)
+
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:         audit.ActorFromContext(ctx),
+		Action:        "billing.change_plan",
+		TargetType:    "StripeSubscription",
+		TargetID:      updated.ID.String(),
+		BeforeHash:    mustHashState(sub.Status, sub.StripePriceID),
+		AfterHash:     mustHashState(updated.Status, updated.StripePriceID),
+		RequestID:     audit.RequestIDFromContext(ctx),
+		CorrelationID: stripeRequestID(updatedStripeSub),
+	})
 	return updated, nil
 }
 
+// stripeRequestID returns the Stripe request ID the API attached to sub's
+// last response, for use as Record.CorrelationID, or "" if sub carries none
+// (e.g. a value built in a test rather than returned by the SDK).
+func stripeRequestID(sub *stripe.Subscription) string {
+	if sub == nil || sub.LastResponse == nil {
+		return ""
+	}
+	return sub.LastResponse.RequestID
+}
+
+// mustHashState hashes v for Record.BeforeHash/AfterHash, swallowing the
+// (impossible for these inputs) marshal error since this is best-effort
+// instrumentation, not something that should fail the billing operation.
+func mustHashState(v ...interface{}) string {
+	hash, err := audit.HashState(v)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
 // CancelSubscriptionAtEnd cancels a subscription at the end of the current billing period.
 // It updates Stripe and sets the local DB status to "canceling".
 func CancelSubscriptionAtEnd(ctx context.Context, client *ent.Client, stripeClient *StripeClient, ownerID string) (*ent.StripeSubscription, error) {
@@ -67,7 +102,8 @@ func CancelSubscriptionAtEnd(ctx context.Context, client *ent.Client, stripeClie
 		return nil, fmt.Errorf("no active subscription found: %w", err)
 	}
 
-	if _, err := stripeClient.CancelSubscriptionAtPeriodEnd(sub.StripeSubscriptionID); err != nil {
+	canceledStripeSub, err := stripeClient.CancelSubscriptionAtPeriodEnd(sub.StripeSubscriptionID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -79,5 +115,16 @@ func CancelSubscriptionAtEnd(ctx context.Context, client *ent.Client, stripeClie
 	}
 
 	log.Printf("[BILLING] action=cancellation_request owner=%s sub=%s", ownerID, sub.StripeSubscriptionID)
+
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:         audit.ActorFromContext(ctx),
+		Action:        "billing.cancel_at_period_end",
+		TargetType:    "StripeSubscription",
+		TargetID:      updated.ID.String(),
+		BeforeHash:    mustHashState(sub.Status),
+		AfterHash:     mustHashState(updated.Status),
+		RequestID:     audit.RequestIDFromContext(ctx),
+		CorrelationID: stripeRequestID(canceledStripeSub),
+	})
 	return updated, nil
 }
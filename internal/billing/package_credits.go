@@ -0,0 +1,121 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"volaticloud/internal/analytics"
+	"volaticloud/internal/db"
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/ent/credittransaction"
+	"volaticloud/internal/enum"
+)
+
+// ExpirePackageCredits walks every org holding unused package/promo credit
+// whose expiry has passed, claws it back via a compensating
+// CreditTxPackageExpiry ledger entry, and flags (through analytics.Notify)
+// any org that still has a positive balance afterwards for manual review —
+// that remaining balance came from non-package credit (subscription/manual
+// deposits, admin adjustments) the package grant didn't cover.
+// Modeled on Storj's expired-package-credit removal job.
+func ExpirePackageCredits(ctx context.Context, client *ent.Client) error {
+	now := time.Now()
+
+	expiredLots, err := client.CreditTransaction.Query().
+		Where(
+			credittransaction.TypeEQ(enum.CreditTxPackageCredit),
+			credittransaction.RemainingAmountGT(0),
+			credittransaction.ExpiresAtLTE(now),
+		).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query expired package credit lots: %w", err)
+	}
+
+	byOwner := make(map[string][]*ent.CreditTransaction)
+	for _, lot := range expiredLots {
+		byOwner[lot.OwnerID] = append(byOwner[lot.OwnerID], lot)
+	}
+
+	for ownerID, lots := range byOwner {
+		if err := expireOwnerPackageCredits(ctx, client, ownerID, lots); err != nil {
+			log.Printf("[BILLING] action=expire_package_credits_fail owner=%s error=%v", ownerID, err)
+		}
+	}
+
+	return nil
+}
+
+func expireOwnerPackageCredits(ctx context.Context, client *ent.Client, ownerID string, lots []*ent.CreditTransaction) error {
+	var expiring float64
+	for _, lot := range lots {
+		expiring += *lot.RemainingAmount
+	}
+	if expiring <= 0 {
+		return nil
+	}
+
+	var newBalance float64
+	err := db.WithTx(ctx, client, func(tx *ent.Tx) error {
+		bal, err := tx.CreditBalance.Query().
+			Where(creditbalance.OwnerID(ownerID)).
+			Only(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get credit balance: %w", err)
+		}
+
+		newBalance = bal.Balance - expiring
+		if newBalance < 0 {
+			newBalance = 0
+		}
+
+		update := tx.CreditBalance.UpdateOneID(bal.ID).SetBalance(newBalance)
+		if newBalance <= 0 && !bal.Suspended {
+			update = update.SetSuspended(true).SetSuspendedAt(time.Now())
+			log.Printf("[BILLING] action=suspend owner=%s reason=balance_depleted", ownerID)
+		}
+		if _, err := update.Save(ctx); err != nil {
+			return fmt.Errorf("failed to update balance: %w", err)
+		}
+
+		for _, lot := range lots {
+			if _, err := tx.CreditTransaction.UpdateOneID(lot.ID).
+				SetRemainingAmount(0).
+				Save(ctx); err != nil {
+				return fmt.Errorf("failed to clear lot %s: %w", lot.ID, err)
+			}
+		}
+
+		_, err = tx.CreditTransaction.Create().
+			SetOwnerID(ownerID).
+			SetAmount(-expiring).
+			SetBalanceAfter(newBalance).
+			SetType(enum.CreditTxPackageExpiry).
+			SetDescription(fmt.Sprintf("Expired package credit (%d lot(s))", len(lots))).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to record expiry transaction: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[BILLING] action=package_credit_expired owner=%s amount=%.2f balance=%.2f", ownerID, expiring, newBalance)
+
+	if newBalance > 0 {
+		_ = analytics.Notify(ctx, analytics.Event{
+			Type:    "package_credit_reconciliation_needed",
+			OwnerID: ownerID,
+			Message: fmt.Sprintf("org still holds %.2f in non-package credit after package expiry", newBalance),
+			Data:    map[string]interface{}{"remaining_balance": newBalance, "expired_amount": expiring},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/analytics"
+	"volaticloud/internal/ent/enttest"
+	"volaticloud/internal/enum"
+)
+
+func TestAddPackageCredits(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:billing_package_credits?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+	bal, err := AddPackageCredits(ctx, client, "org-1", 50, expiresAt, "pkg-starter")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, bal.Balance)
+
+	// Re-applying the same package is idempotent.
+	bal, err = AddPackageCredits(ctx, client, "org-1", 50, expiresAt, "pkg-starter")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, bal.Balance)
+}
+
+func TestDeductCredits_ConsumesPackageLotsByExpiryFirst(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:billing_package_credits_fifo?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	soonExpiry := time.Now().Add(24 * time.Hour)
+	laterExpiry := time.Now().Add(30 * 24 * time.Hour)
+
+	_, err := AddPackageCredits(ctx, client, "org-1", 20, laterExpiry, "pkg-later")
+	require.NoError(t, err)
+	_, err = AddPackageCredits(ctx, client, "org-1", 10, soonExpiry, "pkg-soon")
+	require.NoError(t, err)
+	_, err = AddCredits(ctx, client, "org-1", 100, enum.CreditTxManualDeposit, "manual top-up", "")
+	require.NoError(t, err)
+
+	_, err = DeductCredits(ctx, client, "org-1", 15, "usage", "")
+	require.NoError(t, err)
+
+	txs, err := client.CreditTransaction.Query().All(ctx)
+	require.NoError(t, err)
+	var soonRemaining, laterRemaining float64
+	for _, tx := range txs {
+		if tx.PackageID == "pkg-soon" {
+			require.NotNil(t, tx.RemainingAmount)
+			soonRemaining = *tx.RemainingAmount
+		}
+		if tx.PackageID == "pkg-later" {
+			require.NotNil(t, tx.RemainingAmount)
+			laterRemaining = *tx.RemainingAmount
+		}
+	}
+
+	assert.Equal(t, 0.0, soonRemaining, "the soon-to-expire lot should be consumed first")
+	assert.Equal(t, 15.0, laterRemaining, "the later-expiring lot should absorb only the 5 the soon lot couldn't cover")
+
+	bal, err := GetBalance(ctx, client, "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, 115.0, bal.Balance)
+}
+
+func TestExpirePackageCredits(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:billing_package_credits_expire?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	var notified []analytics.Event
+	analytics.Init(notifierFunc(func(_ context.Context, event analytics.Event) error {
+		notified = append(notified, event)
+		return nil
+	}))
+	defer analytics.Init(nil)
+
+	past := time.Now().Add(-time.Hour)
+	_, err := AddPackageCredits(ctx, client, "org-1", 30, past, "pkg-expired")
+	require.NoError(t, err)
+	_, err = AddCredits(ctx, client, "org-1", 20, enum.CreditTxManualDeposit, "manual top-up", "")
+	require.NoError(t, err)
+
+	require.NoError(t, ExpirePackageCredits(ctx, client))
+
+	bal, err := GetBalance(ctx, client, "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, bal.Balance)
+
+	require.Len(t, notified, 1)
+	assert.Equal(t, "package_credit_reconciliation_needed", notified[0].Type)
+	assert.Equal(t, "org-1", notified[0].OwnerID)
+}
+
+type notifierFunc func(ctx context.Context, event analytics.Event) error
+
+func (f notifierFunc) Notify(ctx context.Context, event analytics.Event) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,108 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"volaticloud/internal/audit"
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/enum"
+)
+
+// ErrOrgPendingDeletion is returned by EnsureSufficientCredits when the
+// organization has been marked for deletion via MarkPendingDeletion.
+// Unlike suspension (recoverable by adding credits), pending deletion is a
+// one-way trip, so callers should surface a distinct error rather than the
+// generic "add credits to continue" message.
+var ErrOrgPendingDeletion = errors.New("organization is pending deletion")
+
+// MarkPendingDeletion flags an organization for deletion. Billing is halted
+// immediately (EnsureSufficientCredits, the invoice generator, and the
+// payment/subscription webhooks all start refusing the org), but the org's
+// data is only soft-deleted once gracePeriod elapses and
+// ProcessPendingDeletions picks it up, mirroring Storj's
+// UserStatus.PendingDeletion grace window.
+func MarkPendingDeletion(ctx context.Context, client *ent.Client, ownerID string, gracePeriod time.Duration) (*ent.CreditBalance, error) {
+	if err := EnsureBalanceExists(ctx, client, ownerID); err != nil {
+		return nil, err
+	}
+
+	bal, err := client.CreditBalance.Query().
+		Where(creditbalance.OwnerID(ownerID)).
+		Only(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit balance: %w", err)
+	}
+
+	now := time.Now()
+	graceUntil := now.Add(gracePeriod)
+
+	updated, err := client.CreditBalance.UpdateOneID(bal.ID).
+		SetLifecycleStatus(enum.OrgStatusPendingDeletion).
+		SetPendingDeletionAt(now).
+		SetDeletionGraceUntil(graceUntil).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark organization pending deletion: %w", err)
+	}
+
+	StopOrgBots(ctx, client, ownerID)
+
+	log.Printf("[BILLING] action=mark_pending_deletion owner=%s grace_until=%s", ownerID, graceUntil.Format(time.RFC3339))
+
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     "billing.mark_pending_deletion",
+		TargetType: "CreditBalance",
+		TargetID:   updated.ID.String(),
+		BeforeHash: mustHashState(bal.LifecycleStatus),
+		AfterHash:  mustHashState(updated.LifecycleStatus),
+	})
+
+	return updated, nil
+}
+
+// ProcessPendingDeletions soft-deletes the CreditBalance of every
+// organization whose deletion grace period has elapsed. Intended to run
+// periodically from a background job. Returns the number of organizations
+// transitioned.
+func ProcessPendingDeletions(ctx context.Context, client *ent.Client) (int, error) {
+	expired, err := client.CreditBalance.Query().
+		Where(
+			creditbalance.LifecycleStatusEQ(enum.OrgStatusPendingDeletion),
+			creditbalance.DeletionGraceUntilLTE(time.Now()),
+		).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query pending deletions: %w", err)
+	}
+
+	transitioned := 0
+	for _, bal := range expired {
+		if err := client.CreditBalance.DeleteOneID(bal.ID).Exec(ctx); err != nil {
+			log.Printf("[BILLING] action=process_pending_deletion_fail owner=%s error=%v", bal.OwnerID, err)
+			continue
+		}
+		transitioned++
+		log.Printf("[BILLING] action=org_soft_deleted owner=%s", bal.OwnerID)
+	}
+
+	return transitioned, nil
+}
+
+// isPendingDeletion reports whether ownerID's CreditBalance is in the
+// pending-deletion state. A missing balance is treated as not pending, same
+// as EnsureSufficientCredits' "no balance record" allow-through.
+func isPendingDeletion(ctx context.Context, client *ent.Client, ownerID string) bool {
+	bal, err := client.CreditBalance.Query().
+		Where(creditbalance.OwnerID(ownerID)).
+		Only(ctx)
+	if err != nil {
+		return false
+	}
+	return bal.LifecycleStatus == enum.OrgStatusPendingDeletion
+}
@@ -0,0 +1,176 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/db"
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/ent/enttest"
+	"volaticloud/internal/ent/mixin"
+	"volaticloud/internal/enum"
+)
+
+func TestMarkPendingDeletion(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:pending_deletion_mark?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	bal, err := MarkPendingDeletion(ctx, client, "org-1", 48*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, enum.OrgStatusPendingDeletion, bal.LifecycleStatus)
+	require.NotNil(t, bal.PendingDeletionAt)
+	require.NotNil(t, bal.DeletionGraceUntil)
+	assert.True(t, bal.DeletionGraceUntil.After(time.Now().Add(47*time.Hour)))
+}
+
+func TestEnsureSufficientCredits_PendingDeletion(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:pending_deletion_enforcement?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err := AddCredits(ctx, client, "org-1", 100, enum.CreditTxManualDeposit, "seed", "")
+	require.NoError(t, err)
+
+	_, err = MarkPendingDeletion(ctx, client, "org-1", 48*time.Hour)
+	require.NoError(t, err)
+
+	err = EnsureSufficientCredits(ctx, client, "org-1")
+	assert.True(t, errors.Is(err, ErrOrgPendingDeletion))
+}
+
+func TestPrepareInvoiceRecords_SkipsPendingDeletion(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:pending_deletion_invoice?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	periodStart := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	createActiveSubscription(ctx, t, client, "org-1", "sub-1", 100, []string{"live_trading"})
+	_, err := client.CreditTransaction.Create().
+		SetOwnerID("org-1").
+		SetAmount(-10).
+		SetBalanceAfter(0).
+		SetType(enum.CreditTxUsageDeduction).
+		SetDescription("usage").
+		SetCreatedAt(periodStart.Add(time.Hour)).
+		Save(ctx)
+	require.NoError(t, err)
+
+	_, err = MarkPendingDeletion(ctx, client, "org-1", 48*time.Hour)
+	require.NoError(t, err)
+
+	svc := NewInvoiceService(client, nil)
+	records, err := svc.PrepareInvoiceRecords(ctx, periodStart, periodEnd)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestWebhooks_RefuseOnPendingDeletion(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:pending_deletion_webhooks?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	createActiveSubscription(ctx, t, client, "org-1", "sub-1", 100, []string{"live_trading"})
+	_, err := MarkPendingDeletion(ctx, client, "org-1", 48*time.Hour)
+	require.NoError(t, err)
+
+	before, err := GetBalance(ctx, client, "org-1")
+	require.NoError(t, err)
+
+	invoice := map[string]interface{}{
+		"id":             "inv-1",
+		"billing_reason": "subscription_cycle",
+		"parent": map[string]interface{}{
+			"subscription_details": map[string]interface{}{
+				"subscription": map[string]interface{}{
+					"id": "sub-1",
+				},
+			},
+		},
+	}
+	require.NoError(t, handleInvoicePaymentSucceeded(ctx, client, nil, makeEvent("invoice.payment_succeeded", invoice)))
+
+	after, err := GetBalance(ctx, client, "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, before.Balance, after.Balance, "pending-deletion org should not receive a renewal deposit")
+
+	subUpdate := map[string]interface{}{
+		"id":                   "sub-1",
+		"status":               "active",
+		"cancel_at_period_end": false,
+		"items": map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{
+					"current_period_start": time.Now().Unix(),
+					"current_period_end":   time.Now().Add(30 * 24 * time.Hour).Unix(),
+					"price": map[string]interface{}{
+						"product": map[string]interface{}{
+							"name": "Enterprise Plan",
+							"metadata": map[string]interface{}{
+								"display_name":    "Enterprise",
+								"monthly_deposit": "200",
+								"features":        "live_trading,backtesting,code_mode",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, handleSubscriptionUpdated(ctx, client, nil, makeEvent("customer.subscription.updated", subUpdate)))
+
+	assert.Error(t, HasFeature(ctx, client, "org-1", "code_mode"), "subscription update should have been refused, so the plan upgrade never applied")
+}
+
+func TestProcessPendingDeletions(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:pending_deletion_sweep?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	db.SetupSoftDelete(client)
+	ctx := context.Background()
+
+	_, err := MarkPendingDeletion(ctx, client, "org-expired", time.Hour)
+	require.NoError(t, err)
+	_, err = MarkPendingDeletion(ctx, client, "org-not-yet", 48*time.Hour)
+	require.NoError(t, err)
+
+	// Backdate org-expired's grace period so the sweep picks it up.
+	expired, err := client.CreditBalance.Query().
+		Where(creditbalance.OwnerID("org-expired")).
+		Only(ctx)
+	require.NoError(t, err)
+	_, err = client.CreditBalance.UpdateOneID(expired.ID).
+		SetDeletionGraceUntil(time.Now().Add(-time.Minute)).
+		Save(ctx)
+	require.NoError(t, err)
+
+	n, err := ProcessPendingDeletions(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = client.CreditBalance.Query().
+		Where(creditbalance.OwnerID("org-expired")).
+		Only(ctx)
+	assert.True(t, ent.IsNotFound(err), "soft-deleted org should be hidden from default queries")
+
+	includeDeletedCtx := mixin.IncludeDeleted(ctx)
+	deleted, err := client.CreditBalance.Query().
+		Where(creditbalance.OwnerID("org-expired")).
+		Only(includeDeletedCtx)
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+
+	notYet, err := client.CreditBalance.Query().
+		Where(creditbalance.OwnerID("org-not-yet")).
+		Only(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, notYet.DeletedAt)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stripe/stripe-go/v82/checkout/session"
 	"github.com/stripe/stripe-go/v82/customer"
 	"github.com/stripe/stripe-go/v82/invoice"
+	"github.com/stripe/stripe-go/v82/invoiceitem"
 	"github.com/stripe/stripe-go/v82/product"
 	"github.com/stripe/stripe-go/v82/subscription"
 )
@@ -209,6 +210,44 @@ func (s *StripeClient) GetSubscription(subscriptionID string) (*stripe.Subscript
 	return sub, nil
 }
 
+// CreateInvoiceItem adds a pending line item to the customer's next draft invoice.
+// amountCents may be used directly since invoice items are denominated in cents.
+func (s *StripeClient) CreateInvoiceItem(customerID string, amountCents int64, description string) (*stripe.InvoiceItem, error) {
+	params := &stripe.InvoiceItemParams{
+		Customer:    stripe.String(customerID),
+		Amount:      stripe.Int64(amountCents),
+		Currency:    stripe.String("usd"),
+		Description: stripe.String(description),
+	}
+	item, err := invoiceitem.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Stripe invoice item: %w", err)
+	}
+	return item, nil
+}
+
+// CreateDraftInvoice creates a draft invoice for the customer, pulling in any
+// pending invoice items created via CreateInvoiceItem.
+func (s *StripeClient) CreateDraftInvoice(customerID string) (*stripe.Invoice, error) {
+	params := &stripe.InvoiceParams{
+		Customer: stripe.String(customerID),
+	}
+	inv, err := invoice.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Stripe invoice: %w", err)
+	}
+	return inv, nil
+}
+
+// FinalizeInvoice finalizes a draft invoice, making it payable.
+func (s *StripeClient) FinalizeInvoice(invoiceID string) (*stripe.Invoice, error) {
+	inv, err := invoice.FinalizeInvoice(invoiceID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize Stripe invoice: %w", err)
+	}
+	return inv, nil
+}
+
 // ListInvoices retrieves recent invoices for a Stripe customer.
 func (s *StripeClient) ListInvoices(customerID string, limit int64) ([]*stripe.Invoice, error) {
 	params := &stripe.InvoiceListParams{
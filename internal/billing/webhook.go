@@ -136,6 +136,11 @@ func handleInvoicePaymentSucceeded(ctx context.Context, client *ent.Client, stri
 		return fmt.Errorf("subscription not found for %s: %w", subscriptionID, err)
 	}
 
+	if isPendingDeletion(ctx, client, sub.OwnerID) {
+		log.Printf("[BILLING] action=deposit_skip invoice=%s owner=%s reason=pending_deletion", invoice.ID, sub.OwnerID)
+		return nil
+	}
+
 	return ProcessSubscriptionDeposit(ctx, client, sub.OwnerID, invoice.ID)
 }
 
@@ -156,6 +161,11 @@ func handleSubscriptionUpdated(ctx context.Context, client *ent.Client, stripeCl
 		return fmt.Errorf("failed to query subscription: %w", err)
 	}
 
+	if isPendingDeletion(ctx, client, sub.OwnerID) {
+		log.Printf("[BILLING] action=subscription_update_skip sub=%s owner=%s reason=pending_deletion", stripeSub.ID, sub.OwnerID)
+		return nil
+	}
+
 	// Map Stripe status to our enum
 	status := mapStripeStatusWithCancel(string(stripeSub.Status), stripeSub.CancelAtPeriodEnd)
 
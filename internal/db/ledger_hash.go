@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+
+	entclient "volaticloud/internal/ent"
+	"volaticloud/internal/ent/creditbalance"
+	"volaticloud/internal/ent/credittransaction"
+)
+
+// LedgerHashInput captures the CreditTransaction fields that are hashed into
+// the chain. This shape is part of the hash, so it is append-only: add
+// fields to the end, never remove, rename, or reorder an existing one, or
+// every row hashed before the change will appear tampered. Exported so
+// billing.VerifyLedger can recompute the same hash from a persisted row.
+type LedgerHashInput struct {
+	OwnerID       string  `json:"owner_id"`
+	Amount        float64 `json:"amount"`
+	BalanceAfter  float64 `json:"balance_after"`
+	Type          string  `json:"type"`
+	Description   string  `json:"description"`
+	ReferenceID   string  `json:"reference_id"`
+	PackageID     string  `json:"package_id"`
+	CreatedAtUnix int64   `json:"created_at_unix"`
+}
+
+// HashLedgerEntry returns sha256(canonical JSON of input || prevHash), hex-encoded.
+func HashLedgerEntry(input LedgerHashInput, prevHash string) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ledger entry for hashing: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write(data)
+	h.Write([]byte(prevHash))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SetupLedgerHashChain configures a hook on CreditTransaction.Create that
+// stamps prev_hash/hash, chaining each new row to the most recently created
+// row for the same owner_id. Paired with the schema's append-only delete
+// rejection, this lets billing.VerifyLedger detect any row that was altered
+// after being written (e.g. via a direct SQL UPDATE bypassing ent).
+//
+// Two concurrent creates for the same owner_id would otherwise both read the
+// same "latest" row and stamp the same prev_hash, forking the chain. This is
+// guarded against two ways: first, by locking the owner's CreditBalance row
+// with SELECT ... FOR UPDATE before looking up the previous entry, so a
+// second concurrent create blocks here until the first one's transaction
+// commits (or rolls back) and sees its result. FOR UPDATE isn't supported on
+// SQLite, so the lock is skipped there; the unique index on
+// (owner_id, prev_hash) is the backstop for that case (and for any caller
+// that creates a CreditTransaction without ever touching CreditBalance) —
+// a fork still can't silently commit, it fails the INSERT instead.
+func SetupLedgerHashChain(client *entclient.Client) {
+	client.CreditTransaction.Use(func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			mut, ok := m.(*entclient.CreditTransactionMutation)
+			if !ok || mut.Op() != ent.OpCreate {
+				return next.Mutate(ctx, m)
+			}
+
+			ownerID, ok := mut.OwnerID()
+			if !ok {
+				return nil, fmt.Errorf("credit transaction missing owner_id")
+			}
+
+			_, err := mut.Client().CreditBalance.Query().
+				Where(creditbalance.OwnerID(ownerID)).
+				Where(func(s *sql.Selector) {
+					if s.Dialect() != dialect.SQLite {
+						s.ForUpdate()
+					}
+				}).
+				Only(ctx)
+			if err != nil && !entclient.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to lock credit balance for %s: %w", ownerID, err)
+			}
+
+			prevHash := ""
+			prev, err := mut.Client().CreditTransaction.Query().
+				Where(credittransaction.OwnerID(ownerID)).
+				Order(ent.Desc(credittransaction.FieldCreatedAt), ent.Desc(credittransaction.FieldID)).
+				First(ctx)
+			if err != nil && !entclient.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to look up previous ledger entry: %w", err)
+			}
+			if err == nil {
+				prevHash = prev.Hash
+			}
+
+			amount, _ := mut.Amount()
+			balanceAfter, _ := mut.BalanceAfter()
+			txType, _ := mut.GetType()
+			description, _ := mut.Description()
+			referenceID, _ := mut.ReferenceID()
+			packageID, _ := mut.PackageID()
+			createdAt, _ := mut.CreatedAt()
+
+			hash, err := HashLedgerEntry(LedgerHashInput{
+				OwnerID:       ownerID,
+				Amount:        amount,
+				BalanceAfter:  balanceAfter,
+				Type:          string(txType),
+				Description:   description,
+				ReferenceID:   referenceID,
+				PackageID:     packageID,
+				CreatedAtUnix: createdAt.Unix(),
+			}, prevHash)
+			if err != nil {
+				return nil, err
+			}
+
+			mut.SetPrevHash(prevHash)
+			mut.SetHash(hash)
+
+			return next.Mutate(ctx, m)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/alertrule"
+	"volaticloud/internal/ent/bot"
+	"volaticloud/internal/ent/mixin"
+	"volaticloud/internal/ent/resourceusageaggregation"
+	"volaticloud/internal/ent/trade"
+)
+
+// PurgeSummary reports how many rows PurgeExpired removed per entity.
+type PurgeSummary struct {
+	AlertRules         int
+	Bots               int
+	Trades             int
+	ResourceUsageAggrs int
+}
+
+// PurgeExpired hard-deletes soft-deleted rows whose deleted_at is older than
+// olderThan, for AlertRule, Trade, Bot and ResourceUsageAggregation.
+// CreditBalance also embeds mixin.SoftDeleteMixin but its soft-deleted rows
+// are not purged here. It mirrors
+// the pending_deletion-to-deleted retention sweep used for org lifecycle:
+// soft-delete marks a row as gone immediately, this permanently removes it
+// once the retention window has elapsed.
+//
+// Trade is purged before Bot since Trade.bot_id is a required, non-cascading
+// edge to Bot: deleting a Bot while its expired Trades still exist would
+// violate the foreign key on an FK-enforcing database, or silently orphan
+// those Trade rows on one that doesn't. All four deletes run in a single
+// transaction so a failure partway through the sweep doesn't leave the DB
+// with some entities purged and others not.
+func PurgeExpired(ctx context.Context, client *ent.Client, olderThan time.Time) (PurgeSummary, error) {
+	ctx = WithHardDelete(ctx)
+	ctx = mixin.IncludeDeleted(ctx)
+
+	var summary PurgeSummary
+
+	err := WithTx(ctx, client, func(tx *ent.Tx) error {
+		n, err := tx.AlertRule.Delete().
+			Where(alertrule.DeletedAtLTE(olderThan)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		summary.AlertRules = n
+
+		n, err = tx.Trade.Delete().
+			Where(trade.DeletedAtLTE(olderThan)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		summary.Trades = n
+
+		n, err = tx.Bot.Delete().
+			Where(bot.DeletedAtLTE(olderThan)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		summary.Bots = n
+
+		n, err = tx.ResourceUsageAggregation.Delete().
+			Where(resourceusageaggregation.DeletedAtLTE(olderThan)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		summary.ResourceUsageAggrs = n
+
+		return nil
+	})
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	return summary, nil
+}
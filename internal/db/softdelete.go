@@ -6,7 +6,11 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"github.com/google/uuid"
+
 	entclient "volaticloud/internal/ent"
+	"volaticloud/internal/ent/bot"
+	"volaticloud/internal/ent/trade"
 )
 
 // softDeleteKey is the context key for hard delete operations.
@@ -68,7 +72,9 @@ func SetupSoftDelete(client *entclient.Client) {
 		})
 	})
 
-	// Add soft-delete hook to Bot
+	// Add soft-delete hook to Bot. Bot declares mixin.SoftDeleteMixin{}.WithCascade("trades"),
+	// so deleting a Bot also soft-deletes its trades, sharing a deleted_batch_id so
+	// mixin.Restore can undo the whole batch together.
 	client.Bot.Use(func(next ent.Mutator) ent.Mutator {
 		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
 			mut, ok := m.(*entclient.BotMutation)
@@ -87,7 +93,18 @@ func SetupSoftDelete(client *entclient.Client) {
 			}
 			now := time.Now()
 			for _, id := range ids {
-				if _, err := mut.Client().Bot.UpdateOneID(id).SetDeletedAt(now).Save(ctx); err != nil {
+				batchID := uuid.New()
+				if _, err := mut.Client().Bot.UpdateOneID(id).
+					SetDeletedAt(now).
+					SetDeletedBatchID(batchID).
+					Save(ctx); err != nil {
+					return nil, err
+				}
+				if _, err := mut.Client().Trade.Update().
+					Where(trade.HasBotWith(bot.ID(id))).
+					SetDeletedAt(now).
+					SetDeletedBatchID(batchID).
+					Save(ctx); err != nil {
 					return nil, err
 				}
 			}
@@ -283,4 +300,34 @@ func SetupSoftDelete(client *entclient.Client) {
 			return nil, nil
 		})
 	})
-}
\ No newline at end of file
+
+	// Add soft-delete hook to CreditBalance. billing.ProcessPendingDeletions
+	// calls client.CreditBalance.DeleteOneID() once an org's grace period has
+	// elapsed, which this hook converts into the same soft-delete as every
+	// other entity here.
+	client.CreditBalance.Use(func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			mut, ok := m.(*entclient.CreditBalanceMutation)
+			if !ok {
+				return next.Mutate(ctx, m)
+			}
+			if !mut.Op().Is(ent.OpDelete | ent.OpDeleteOne) {
+				return next.Mutate(ctx, m)
+			}
+			if isHardDelete(ctx) {
+				return next.Mutate(ctx, m)
+			}
+			ids, err := mut.IDs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			now := time.Now()
+			for _, id := range ids {
+				if _, err := mut.Client().CreditBalance.UpdateOneID(id).SetDeletedAt(now).Save(ctx); err != nil {
+					return nil, err
+				}
+			}
+			return nil, nil
+		})
+	})
+}
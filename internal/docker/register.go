@@ -49,12 +49,19 @@ func init() {
 		return btRunner, nil
 	})
 
-	// Register Docker config validator
+	// Register Docker config validator (legacy fallback, consulted by
+	// runner.ValidateConfig only when the schema registry reports
+	// ErrNoSchemaRegistered; a schema that fails to compile panics below)
 	runner.RegisterConfigValidator(enum.RunnerDocker, func(configData map[string]interface{}) error {
 		_, err := ParseConfig(configData)
 		return err
 	})
 
+	// Register Docker config schema
+	if err := runner.RegisterConfigSchema(enum.RunnerDocker, 1, []byte(configSchemaV1)); err != nil {
+		panic(fmt.Sprintf("failed to register docker config schema: %v", err))
+	}
+
 	// Register Docker data downloader creator
 	runner.RegisterDataDownloaderCreator(enum.RunnerDocker, func(ctx context.Context, configData map[string]interface{}) (runner.DataDownloader, error) {
 		config, err := ParseConfig(configData)
@@ -0,0 +1,32 @@
+package docker
+
+// configSchemaV1 is the draft-2020-12 JSON Schema for the Docker runner
+// config, served by the admin schema endpoint and used by
+// runner.ValidateConfig to structurally validate payloads before ParseConfig
+// unmarshals them into Config.
+const configSchemaV1 = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://volaticloud.dev/schemas/runner/docker/v1.json",
+	"title": "Docker runner config",
+	"type": "object",
+	"properties": {
+		"version": { "type": "integer", "const": 1 },
+		"host": { "type": "string", "minLength": 1 },
+		"tlsVerify": { "type": "boolean" },
+		"certPEM": { "type": "string" },
+		"keyPEM": { "type": "string" },
+		"caPEM": { "type": "string" },
+		"apiVersion": { "type": "string" },
+		"network": { "type": "string" },
+		"registryAuth": {
+			"type": "object",
+			"properties": {
+				"username": { "type": "string", "minLength": 1 },
+				"password": { "type": "string", "minLength": 1 },
+				"serverAddress": { "type": "string" }
+			},
+			"required": ["username", "password"]
+		}
+	},
+	"required": ["host"]
+}`
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
 
 	"volaticloud/internal/ent/intercept"
 )
@@ -57,8 +58,37 @@ import (
 //	ctx := db.WithHardDelete(ctx)
 //	ctx = mixin.IncludeDeleted(ctx) // needed to find soft-deleted records
 //	client.Entity.DeleteOneID(id).Exec(ctx)
+//
+// # Cascading soft-delete
+//
+// WithCascade declares which of the schema's edges should be soft-deleted
+// alongside the parent, sharing its deleted_batch_id:
+//
+//	func (Bot) Mixin() []ent.Mixin {
+//	    return []ent.Mixin{
+//	        mixin.SoftDeleteMixin{}.WithCascade("trades"),
+//	    }
+//	}
+//
+// CascadeEdges() only records the declaration; the matching soft-delete hook
+// in db.SetupSoftDelete is what actually walks the edge and stamps the
+// children, the same as every other entity's hook in that file.
 type SoftDeleteMixin struct {
 	mixin.Schema
+
+	cascadeEdges []string
+}
+
+// WithCascade returns a copy of the mixin that declares edges (by name) whose
+// targets should be soft-deleted in the same batch as this entity.
+func (m SoftDeleteMixin) WithCascade(edges ...string) SoftDeleteMixin {
+	m.cascadeEdges = edges
+	return m
+}
+
+// CascadeEdges returns the edge names declared via WithCascade.
+func (m SoftDeleteMixin) CascadeEdges() []string {
+	return m.cascadeEdges
 }
 
 // softDeleteKey is the context key for including deleted records.
@@ -76,13 +106,17 @@ func isIncludeDeleted(ctx context.Context) bool {
 	return ok && include
 }
 
-// Fields returns the deleted_at field for soft-delete.
+// Fields returns the deleted_at and deleted_batch_id fields for soft-delete.
 func (SoftDeleteMixin) Fields() []ent.Field {
 	return []ent.Field{
 		field.Time("deleted_at").
 			Optional().
 			Nillable().
 			Comment("Soft-delete timestamp. If set, record is considered deleted."),
+		field.UUID("deleted_batch_id", uuid.UUID{}).
+			Optional().
+			Nillable().
+			Comment("Groups this delete with any cascaded children so Restore can undo the whole batch together."),
 	}
 }
 
@@ -109,3 +143,21 @@ func (SoftDeleteMixin) Interceptors() []ent.Interceptor {
 func Now() time.Time {
 	return time.Now()
 }
+
+// Restorable is implemented by the generated UpdateOne builder of any entity
+// that embeds SoftDeleteMixin.
+type Restorable[T any] interface {
+	ClearDeletedAt() T
+	ClearDeletedBatchID() T
+}
+
+// Restore clears deleted_at and deleted_batch_id on an update builder,
+// undoing a prior soft-delete. The context passed to the eventual Save must
+// be wrapped in IncludeDeleted, otherwise the builder's own query to locate
+// the (currently hidden) row will find nothing.
+//
+//	ctx := mixin.IncludeDeleted(ctx)
+//	_, err := mixin.Restore(client.Bot.UpdateOneID(id)).Save(ctx)
+func Restore[T Restorable[T]](builder T) T {
+	return builder.ClearDeletedAt().ClearDeletedBatchID()
+}
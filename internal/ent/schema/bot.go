@@ -10,6 +10,7 @@ import (
 	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
 
+	entmixin "anytrade/internal/ent/mixin"
 	"anytrade/internal/enum"
 )
 
@@ -103,3 +104,10 @@ func (Bot) Annotations() []schema.Annotation {
 		entgql.Mutations(entgql.MutationCreate(), entgql.MutationUpdate()),
 	}
 }
+
+// Mixin of the Bot.
+func (Bot) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		entmixin.SoftDeleteMixin{}.WithCascade("trades"),
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
 	"github.com/google/uuid"
+
+	entmixin "volaticloud/internal/ent/mixin"
+	"volaticloud/internal/enum"
 )
 
 // CreditBalance holds the credit balance for an organization.
@@ -37,6 +40,18 @@ func (CreditBalance) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("When the organization was suspended"),
+		field.Enum("lifecycle_status").
+			GoType(enum.OrgStatus("")).
+			Default(string(enum.OrgStatusActive)).
+			Comment("Org billing lifecycle status, independent of credit suspension above"),
+		field.Time("pending_deletion_at").
+			Optional().
+			Nillable().
+			Comment("When MarkPendingDeletion was called"),
+		field.Time("deletion_grace_until").
+			Optional().
+			Nillable().
+			Comment("When the grace period ends and ProcessPendingDeletions may soft-delete this organization"),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -55,6 +70,14 @@ func (CreditBalance) Edges() []ent.Edge {
 func (CreditBalance) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("owner_id").Unique(),
+		index.Fields("lifecycle_status", "deletion_grace_until"),
+	}
+}
+
+// Mixin of the CreditBalance.
+func (CreditBalance) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		entmixin.SoftDeleteMixin{},
 	}
 }
 
@@ -42,6 +42,23 @@ func (CreditTransaction) Fields() []ent.Field {
 		field.String("reference_id").
 			Optional().
 			Comment("For idempotency (e.g. Stripe invoice ID, aggregation bucket key)"),
+		field.String("package_id").
+			Optional().
+			Comment("Promo/package identifier for package_credit deposits"),
+		field.Time("expires_at").
+			Optional().
+			Nillable().
+			Comment("When this credit-granting transaction's funds expire (package/promo credits only)"),
+		field.Float("remaining_amount").
+			Optional().
+			Nillable().
+			Comment("Unconsumed portion of a credit-granting transaction's amount, decremented as DeductCredits consumes it FIFO by expiry"),
+		field.String("prev_hash").
+			Immutable().
+			Comment("Hash of the owner's previous ledger entry, empty for the first transaction. Chains this row to its predecessor so billing.VerifyLedger can detect tampering"),
+		field.String("hash").
+			Immutable().
+			Comment("sha256 of this row's canonical fields plus prev_hash, computed by db.SetupLedgerHashChain on create"),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -58,6 +75,10 @@ func (CreditTransaction) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("owner_id", "created_at"),
 		index.Fields("reference_id").Unique(),
+		// Backstops SetupLedgerHashChain's locking: two entries for the same
+		// owner can never share a prev_hash, so a race that slips past the
+		// lock fails the INSERT instead of silently forking the chain.
+		index.Fields("owner_id", "prev_hash").Unique(),
 	}
 }
 
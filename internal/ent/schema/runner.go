@@ -91,6 +91,10 @@ func (BotRunner) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("Price per GB of disk I/O in USD (only used if billing_enabled)"),
+		field.Text("pricing_plan").
+			Optional().
+			Nillable().
+			Comment("JSON-encoded usage.PricingPlan document for tiered/committed-use pricing. When set, billing prices this runner's usage with usage.PricingEngine instead of the flat cpu/memory/network/storage_price_per_* fields above."),
 
 		field.Time("created_at").
 			Default(time.Now).
@@ -14,6 +14,9 @@ const (
 	CreditTxManualDeposit       CreditTransactionType = "manual_deposit"
 	CreditTxUsageDeduction      CreditTransactionType = "usage_deduction"
 	CreditTxAdminAdjustment     CreditTransactionType = "admin_adjustment"
+	CreditTxInvoiceGenerated    CreditTransactionType = "invoice_generated"
+	CreditTxPackageCredit       CreditTransactionType = "package_credit"
+	CreditTxPackageExpiry       CreditTransactionType = "package_expiry"
 )
 
 // Values returns all possible credit transaction type values
@@ -23,6 +26,9 @@ func (CreditTransactionType) Values() []string {
 		string(CreditTxManualDeposit),
 		string(CreditTxUsageDeduction),
 		string(CreditTxAdminAdjustment),
+		string(CreditTxInvoiceGenerated),
+		string(CreditTxPackageCredit),
+		string(CreditTxPackageExpiry),
 	}
 }
 
@@ -87,3 +93,41 @@ func (s *StripeSubStatus) UnmarshalGQL(v interface{}) error {
 	}
 	return fmt.Errorf("invalid stripe subscription status: %q", str)
 }
+
+// OrgStatus represents an organization's billing lifecycle status,
+// independent of CreditBalance.Suspended (which tracks credit depletion).
+type OrgStatus string
+
+const (
+	OrgStatusActive          OrgStatus = "active"
+	OrgStatusPendingDeletion OrgStatus = "pending_deletion"
+)
+
+// Values returns all possible org status values
+func (OrgStatus) Values() []string {
+	return []string{
+		string(OrgStatusActive),
+		string(OrgStatusPendingDeletion),
+	}
+}
+
+// MarshalGQL implements graphql.Marshaler for OrgStatus
+func (s OrgStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler for OrgStatus
+func (s *OrgStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("org status must be a string")
+	}
+	val := OrgStatus(str)
+	for _, valid := range val.Values() {
+		if str == valid {
+			*s = val
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid org status: %q", str)
+}
@@ -6,8 +6,20 @@ import (
 	"log"
 
 	"github.com/Nerzal/gocloak/v13"
+
+	"volaticloud/internal/audit"
 )
 
+// resourceOwner extracts the "ownerId" attribute CreateResource's callers
+// always populate (see graph.permission_helpers.go), for use as the audit
+// actor when no caller identity is otherwise available.
+func resourceOwner(attributes map[string][]string) string {
+	if vals := attributes["ownerId"]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
 // UMAClient handles UMA 2.0 (User-Managed Access) operations for resource-level authorization
 type UMAClient struct {
 	client       *gocloak.GoCloak
@@ -81,6 +93,18 @@ func (u *UMAClient) CreateResource(ctx context.Context, resourceID, resourceName
 
 	log.Printf("DEBUG: CreateResourceClient succeeded, result: %+v", result)
 	log.Printf("Created Keycloak resource: %s (%s) with scopes: %v", resourceID, resourceName, scopes)
+
+	actor := audit.ActorFromContext(ctx)
+	if actor == "" {
+		actor = resourceOwner(attributes)
+	}
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:      actor,
+		Action:     "keycloak.create_resource",
+		TargetType: "KeycloakResource",
+		TargetID:   resourceID,
+		RequestID:  audit.RequestIDFromContext(ctx),
+	})
 	return nil
 }
 
@@ -100,6 +124,14 @@ func (u *UMAClient) DeleteResource(ctx context.Context, resourceID string) error
 	}
 
 	log.Printf("Deleted Keycloak resource: %s", resourceID)
+
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     "keycloak.delete_resource",
+		TargetType: "KeycloakResource",
+		TargetID:   resourceID,
+		RequestID:  audit.RequestIDFromContext(ctx),
+	})
 	return nil
 }
 
@@ -141,6 +173,18 @@ func (u *UMAClient) CreatePermission(ctx context.Context, resourceID, ownerID st
 	// Full policy creation requires understanding the correct gocloak v13 API
 
 	log.Printf("Permission policy stub for resource %s (owner: %s) - using owner-managed access", resourceID, ownerID)
+
+	actor := audit.ActorFromContext(ctx)
+	if actor == "" {
+		actor = ownerID
+	}
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:      actor,
+		Action:     "keycloak.create_permission",
+		TargetType: "KeycloakResource",
+		TargetID:   resourceID,
+		RequestID:  audit.RequestIDFromContext(ctx),
+	})
 	return nil
 }
 
@@ -49,9 +49,16 @@ func init() {
 		return btRunner, nil
 	})
 
-	// Register Kubernetes config validator
+	// Register Kubernetes config validator (legacy fallback, consulted by
+	// runner.ValidateConfig only when the schema registry reports
+	// ErrNoSchemaRegistered; a schema that fails to compile panics below)
 	runner.RegisterConfigValidator(enum.RunnerKubernetes, func(configData map[string]interface{}) error {
 		_, err := ParseConfig(configData)
 		return err
 	})
+
+	// Register Kubernetes config schema
+	if err := runner.RegisterConfigSchema(enum.RunnerKubernetes, 1, []byte(configSchemaV1)); err != nil {
+		panic(fmt.Sprintf("failed to register kubernetes config schema: %v", err))
+	}
 }
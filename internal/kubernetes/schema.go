@@ -0,0 +1,35 @@
+package kubernetes
+
+// configSchemaV1 is the draft-2020-12 JSON Schema for the Kubernetes runner
+// config, served by the admin schema endpoint and used by
+// runner.ValidateConfig to structurally validate payloads before ParseConfig
+// unmarshals them into Config.
+const configSchemaV1 = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://volaticloud.dev/schemas/runner/kubernetes/v1.json",
+	"title": "Kubernetes runner config",
+	"type": "object",
+	"properties": {
+		"version": { "type": "integer", "const": 1 },
+		"kubeconfig": { "type": "string" },
+		"context": { "type": "string" },
+		"namespace": {
+			"type": "string",
+			"pattern": "^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$"
+		},
+		"storageClassName": { "type": "string" },
+		"sharedDataPVC": { "type": "string" },
+		"freqtradeImage": { "type": "string" },
+		"prometheusUrl": { "type": "string" },
+		"defaultResources": {
+			"type": "object",
+			"properties": {
+				"cpuRequest": { "type": "string" },
+				"cpuLimit": { "type": "string" },
+				"memoryRequest": { "type": "string" },
+				"memoryLimit": { "type": "string" }
+			}
+		}
+	},
+	"required": ["namespace"]
+}`
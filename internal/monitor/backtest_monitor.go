@@ -32,12 +32,19 @@ func NewBacktestMonitor(client *ent.Client, interval time.Duration) *BacktestMon
 
 	return &BacktestMonitor{
 		client:         client,
-		usageCollector: usage.NewCollector(client),
+		usageCollector: usage.NewCollector(client, nil),
 		interval:       interval,
 		stopChan:       make(chan struct{}),
 	}
 }
 
+// SetPrometheusExporter rebuilds the usage collector to feed the given
+// exporter, so samples recorded during monitoring increment its live
+// counters in addition to being persisted.
+func (m *BacktestMonitor) SetPrometheusExporter(exporter *usage.PrometheusExporter) {
+	m.usageCollector = usage.NewCollector(m.client, exporter)
+}
+
 // Start begins monitoring backtests
 func (m *BacktestMonitor) Start(ctx context.Context) {
 	log.Printf("Starting backtest monitor (interval: %v)", m.interval)
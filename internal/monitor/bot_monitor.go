@@ -42,7 +42,7 @@ func NewBotMonitor(dbClient *ent.Client, coordinator *Coordinator) *BotMonitor {
 	return &BotMonitor{
 		dbClient:       dbClient,
 		coordinator:    coordinator,
-		usageCollector: usage.NewCollector(dbClient),
+		usageCollector: usage.NewCollector(dbClient, nil),
 		interval:       DefaultMonitorInterval,
 		stopChan:       make(chan struct{}),
 		doneChan:       make(chan struct{}),
@@ -54,6 +54,13 @@ func (m *BotMonitor) SetInterval(interval time.Duration) {
 	m.interval = interval
 }
 
+// SetPrometheusExporter rebuilds the usage collector to feed the given
+// exporter, so samples recorded during monitoring increment its live
+// counters in addition to being persisted.
+func (m *BotMonitor) SetPrometheusExporter(exporter *usage.PrometheusExporter) {
+	m.usageCollector = usage.NewCollector(m.dbClient, exporter)
+}
+
 // Start begins the monitoring loop
 func (m *BotMonitor) Start(ctx context.Context) error {
 	log.Printf("Starting bot monitor (interval: %v)", m.interval)
@@ -8,6 +8,7 @@ import (
 
 	"volaticloud/internal/ent"
 	"volaticloud/internal/etcd"
+	"volaticloud/internal/usage"
 )
 
 // Manager manages all monitoring workers and coordinates distributed monitoring
@@ -53,6 +54,10 @@ type Config struct {
 	// LeaseTTL is the TTL for etcd leases in seconds
 	// Default: 15s
 	LeaseTTL int64
+
+	// UsagePrometheusExporter, if set, receives every usage sample recorded
+	// by the bot and backtest monitors so it can serve live billing metrics.
+	UsagePrometheusExporter *usage.PrometheusExporter
 }
 
 // NewManager creates a new monitor manager
@@ -128,6 +133,11 @@ func NewManager(cfg Config) (*Manager, error) {
 	// Create backtest monitor (uses same interval as bot monitor)
 	m.backtestMonitor = NewBacktestMonitor(cfg.DatabaseClient, cfg.MonitorInterval)
 
+	if cfg.UsagePrometheusExporter != nil {
+		m.botMonitor.SetPrometheusExporter(cfg.UsagePrometheusExporter)
+		m.backtestMonitor.SetPrometheusExporter(cfg.UsagePrometheusExporter)
+	}
+
 	return m, nil
 }
 
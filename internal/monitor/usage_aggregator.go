@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
@@ -61,6 +62,13 @@ func (w *UsageAggregatorWorker) SetBillingDeductor(deductor BillingDeductor) {
 	w.billingDeductor = deductor
 }
 
+// SetAggregator overrides the usage.Aggregator used for each tick. Pass a
+// *usage.LeaderElectedAggregator (already Start'd) to run this worker's
+// cron tick only while this control-plane replica holds leadership.
+func (w *UsageAggregatorWorker) SetAggregator(aggregator usage.Aggregator) {
+	w.aggregator = aggregator
+}
+
 // Start begins the aggregation loop
 func (w *UsageAggregatorWorker) Start(ctx context.Context) error {
 	log.Printf("Starting usage aggregator worker (interval: %v, retention: %v)", w.interval, w.retention)
@@ -126,6 +134,10 @@ func (w *UsageAggregatorWorker) runAggregation(ctx context.Context) {
 	previousHour := time.Now().Truncate(time.Hour).Add(-time.Hour)
 
 	if err := w.aggregator.AggregateHourly(ctx, previousHour); err != nil {
+		if errors.Is(err, usage.ErrLeaseLost) {
+			log.Println("Skipping usage aggregation: not the leader")
+			return
+		}
 		log.Printf("Failed to aggregate hourly usage: %v", err)
 	} else {
 		log.Printf("Successfully aggregated usage for hour: %v", previousHour)
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"volaticloud/internal/strategy/codegen"
+)
+
+// backtestRequest is the body accepted by BacktestHandler.
+type backtestRequest struct {
+	StrategyCode string               `json:"strategy_code"`
+	Spec         codegen.BacktestSpec `json:"spec"`
+}
+
+// BacktestHandler returns an http.Handler for
+// POST /gateway/v1/bot/{id}/backtest. It renders a Freqtrade backtest config
+// from the request body's BacktestSpec, mounts it into the bot's Freqtrade
+// API, and streams the response - progress updates followed by the resulting
+// trade log - back to the caller through the proxy.
+func (p *BotProxy) BacktestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		botIDStr := chi.URLParam(r, "id")
+		if botIDStr == "" {
+			http.Error(w, "bot ID is required", http.StatusBadRequest)
+			return
+		}
+
+		botID, err := uuid.Parse(botIDStr)
+		if err != nil {
+			http.Error(w, "invalid bot ID format", http.StatusBadRequest)
+			return
+		}
+
+		var req backtestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid backtest request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.StrategyCode == "" {
+			http.Error(w, "strategy_code is required", http.StatusBadRequest)
+			return
+		}
+
+		generator := codegen.NewGenerator()
+		configJSON, err := generator.GenerateBacktestConfig(req.Spec)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid backtest spec: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		targetURL, err := p.getBotTargetURL(r.Context(), botID)
+		if err != nil {
+			p.ErrorHandler(w, r, err)
+			return
+		}
+
+		p.forwardBacktest(w, r, targetURL, req, configJSON)
+	})
+}
+
+// forwardBacktest mounts the generated config alongside the strategy code
+// and forwards the request to the bot's Freqtrade backtesting endpoint,
+// streaming the backend's response straight through to the client.
+func (p *BotProxy) forwardBacktest(w http.ResponseWriter, r *http.Request, targetURL *url.URL, req backtestRequest, configJSON []byte) {
+	payload := map[string]interface{}{
+		"strategy":      req.Spec.StrategyName,
+		"strategy_code": req.StrategyCode,
+		"config":        json.RawMessage(configJSON),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("failed to marshal backtest payload: %w", err))
+		return
+	}
+
+	backendURL := targetURL.String() + "/api/v1/backtest"
+	backendReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, backendURL, bytes.NewReader(body))
+	if err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("failed to build backtest request: %w", err))
+		return
+	}
+	backendReq.Header.Set("Content-Type", "application/json")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		backendReq.Header.Set("Authorization", auth)
+	}
+
+	resp, err := http.DefaultClient.Do(backendReq)
+	if err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("backtest request to bot backend failed: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(resp.StatusCode)
+	streamResponse(w, resp.Body)
+}
+
+// streamResponse copies src to w one chunk at a time, flushing after each
+// write so progress updates reach the client as they arrive instead of
+// sitting in net/http's write buffer until it fills or the handler returns.
+func streamResponse(w http.ResponseWriter, src io.Reader) {
+	flusher, canFlush := w.(http.Flusher)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
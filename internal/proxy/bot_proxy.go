@@ -8,32 +8,82 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"golang.org/x/sync/singleflight"
 
 	"volaticloud/internal/ent"
 	"volaticloud/internal/ent/bot"
 	"volaticloud/internal/runner"
-
-	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
 )
 
+// defaultIdleTimeout is how long either direction of a proxied WebSocket
+// connection may go without a frame before BotProxy closes the connection.
+const defaultIdleTimeout = 60 * time.Second
+
 // BotProxy handles reverse proxy requests to bot containers.
 type BotProxy struct {
 	client  *ent.Client
 	factory *runner.Factory
+
+	// IdleTimeout bounds how long a proxied WebSocket connection (e.g.
+	// Freqtrade's /api/v1/message/ws) may sit idle before it's closed.
+	IdleTimeout time.Duration
+
+	// ErrorHandler is invoked for both plain HTTP proxy errors and
+	// WebSocket dial/upgrade failures, so callers get one place to log or
+	// surface disconnects regardless of which path handled the request.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// RateLimitQPS is the token-bucket refill rate (requests/sec) applied
+	// per bot, used unless the bot's runner config sets rate_limit_qps.
+	RateLimitQPS float64
+
+	// CacheTTL is the default freshness window for cached idempotent GET
+	// responses. CacheTTLByEndpoint overrides it for specific Freqtrade
+	// endpoints (keyed by the path with the /gateway/v1/bot/{id} prefix
+	// stripped, e.g. "/api/v1/status").
+	CacheTTL           time.Duration
+	CacheTTLByEndpoint map[string]time.Duration
+
+	// StaleIfErrorWindow bounds how long a stale cached response may still
+	// be served in place of a 5xx from the bot's backend.
+	StaleIfErrorWindow time.Duration
+
+	rateLimiters sync.Map // botID string -> *tokenBucket
+	cache        sync.Map // cache key -> *cachedResponse
+	cacheStores  int64    // atomic; counts cache.Store calls to trigger periodic sweeps
+	group        singleflight.Group
+	metrics      *proxyMetrics
 }
 
 // NewBotProxy creates a new bot proxy handler.
 func NewBotProxy(client *ent.Client) *BotProxy {
 	return &BotProxy{
-		client:  client,
-		factory: runner.NewFactory(),
+		client:      client,
+		factory:     runner.NewFactory(),
+		IdleTimeout: defaultIdleTimeout,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		},
+		RateLimitQPS:       defaultRateLimitQPS,
+		CacheTTL:           defaultCacheTTL,
+		CacheTTLByEndpoint: defaultCacheTTLByEndpoint(),
+		StaleIfErrorWindow: defaultStaleIfErrorWindow,
+		metrics:            newProxyMetrics(),
 	}
 }
 
 // Handler returns an http.Handler that proxies requests to bot containers.
 // URL pattern: /gateway/v1/bot/{id}/* where {id} is the bot UUID
-// All requests are forwarded to the bot's Freqtrade API.
+// All requests are forwarded to the bot's Freqtrade API. Requests carrying
+// `Connection: Upgrade` / `Upgrade: websocket` (as Freqtrade's
+// /api/v1/message/ws endpoint requires) are proxied frame-by-frame instead
+// of through the standard reverse proxy, which cannot hijack connections.
 func (p *BotProxy) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract bot ID from URL
@@ -68,36 +118,184 @@ func (p *BotProxy) Handler() http.Handler {
 		// Target URL may have a path (e.g., /bot/{id}/ for K8s ingress)
 		fullPath := strings.TrimSuffix(targetURL.Path, "/") + strippedPath
 
-		// Create reverse proxy with base URL (scheme + host only)
-		baseURL := &url.URL{
-			Scheme: targetURL.Scheme,
-			Host:   targetURL.Host,
+		if isWebSocketUpgrade(r) {
+			p.proxyWebSocket(w, r, targetURL, fullPath)
+			return
 		}
-		proxy := httputil.NewSingleHostReverseProxy(baseURL)
-
-		// Customize the director to set the correct path
-		originalDirector := proxy.Director
-		proxy.Director = func(req *http.Request) {
-			originalDirector(req)
 
-			// Set the full path (target path + stripped request path)
-			req.URL.Path = fullPath
-			req.URL.RawPath = fullPath
-
-			// Set host header
-			req.Host = targetURL.Host
+		if !p.allowRequest(r.Context(), botID) {
+			p.metrics.incRateLimited(botIDStr)
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
 		}
 
-		// Custom error handler
-		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		if r.Method == http.MethodGet {
+			p.serveCachedGET(w, r, botIDStr, targetURL, strippedPath, fullPath)
+			return
 		}
 
-		// Forward the request
+		proxy := p.newReverseProxy(targetURL, fullPath)
 		proxy.ServeHTTP(w, r)
 	})
 }
 
+// newReverseProxy builds an httputil.ReverseProxy that forwards to
+// targetURL's scheme/host, rewriting every request's path to fullPath.
+func (p *BotProxy) newReverseProxy(targetURL *url.URL, fullPath string) *httputil.ReverseProxy {
+	baseURL := &url.URL{
+		Scheme: targetURL.Scheme,
+		Host:   targetURL.Host,
+	}
+	proxy := httputil.NewSingleHostReverseProxy(baseURL)
+
+	originalDirector := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		originalDirector(req)
+		req.URL.Path = fullPath
+		req.URL.RawPath = fullPath
+		req.Host = targetURL.Host
+	}
+
+	proxy.ErrorHandler = p.ErrorHandler
+	return proxy
+}
+
+// isWebSocketUpgrade reports whether r is requesting a WebSocket upgrade,
+// per RFC 6455 (a "Connection" header that includes "Upgrade", token-
+// compared case-insensitively since it may be a comma-separated list such
+// as "keep-alive, Upgrade").
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, token := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// hopByHopHeaders are stripped before forwarding request headers to the
+// backend WebSocket dial; gorilla/websocket's Dialer manages the
+// handshake-specific ones itself (Sec-WebSocket-Key/Version/Extensions),
+// and Sec-WebSocket-Protocol is forwarded separately via Dialer.Subprotocols.
+var hopByHopHeaders = []string{
+	"Connection",
+	"Upgrade",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Version",
+	"Sec-Websocket-Extensions",
+	"Sec-Websocket-Protocol",
+	"Host",
+}
+
+// proxyWebSocket dials the bot's backend at fullPath, upgrades the inbound
+// client connection, negotiates the same subprotocol the backend accepted,
+// and shuttles frames both ways until either side closes or either
+// direction goes IdleTimeout without a frame.
+func (p *BotProxy) proxyWebSocket(w http.ResponseWriter, r *http.Request, targetURL *url.URL, fullPath string) {
+	backendURL := &url.URL{
+		Scheme:   wsScheme(targetURL.Scheme),
+		Host:     targetURL.Host,
+		Path:     fullPath,
+		RawQuery: r.URL.RawQuery,
+	}
+
+	forwardHeader := make(http.Header)
+	for name, values := range r.Header {
+		if containsFold(hopByHopHeaders, name) {
+			continue
+		}
+		forwardHeader[name] = values
+	}
+
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+		Subprotocols:     r.Header["Sec-Websocket-Protocol"],
+	}
+
+	backendConn, backendResp, err := dialer.DialContext(r.Context(), backendURL.String(), forwardHeader)
+	if err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("websocket dial to bot backend failed: %w", err))
+		return
+	}
+	defer backendConn.Close()
+
+	upgrader := websocket.Upgrader{
+		HandshakeTimeout: 10 * time.Second,
+		CheckOrigin:      func(r *http.Request) bool { return true },
+		Subprotocols:     r.Header["Sec-Websocket-Protocol"],
+	}
+
+	responseHeader := make(http.Header)
+	if protocol := backendResp.Header.Get("Sec-WebSocket-Protocol"); protocol != "" {
+		responseHeader.Set("Sec-WebSocket-Protocol", protocol)
+	}
+
+	clientConn, err := upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("websocket upgrade of client connection failed: %w", err))
+		return
+	}
+	defer clientConn.Close()
+
+	idleTimeout := p.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- relayWebSocketFrames(clientConn, backendConn, idleTimeout) }()
+	go func() { errc <- relayWebSocketFrames(backendConn, clientConn, idleTimeout) }()
+
+	if err := <-errc; err != nil {
+		p.ErrorHandler(w, r, fmt.Errorf("websocket connection to bot backend closed: %w", err))
+	}
+}
+
+// relayWebSocketFrames copies frames from src to dst until src errors,
+// closes, or goes idleTimeout without a frame. It returns the error that
+// ended the loop, which is nil on a normal close.
+func relayWebSocketFrames(dst, src *websocket.Conn, idleTimeout time.Duration) error {
+	for {
+		if err := src.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+			return err
+		}
+
+		messageType, message, err := src.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return err
+		}
+
+		if err := dst.WriteMessage(messageType, message); err != nil {
+			return err
+		}
+	}
+}
+
+// wsScheme maps the bot backend's HTTP(S) scheme to the corresponding
+// WebSocket scheme.
+func wsScheme(httpScheme string) string {
+	if httpScheme == "https" {
+		return "wss"
+	}
+	return "ws"
+}
+
+// containsFold reports whether name case-insensitively matches any entry in list.
+func containsFold(list []string, name string) bool {
+	for _, candidate := range list {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // getBotTargetURL retrieves the target URL for a bot's Freqtrade API.
 // Uses the runtime's GetBotAPIURL method which handles Docker, Kubernetes, etc.
 func (p *BotProxy) getBotTargetURL(ctx context.Context, botID uuid.UUID) (*url.URL, error) {
@@ -0,0 +1,282 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"volaticloud/internal/ent/bot"
+)
+
+// defaultRateLimitQPS is the token-bucket refill rate applied to a bot whose
+// runner config has no rate_limit_qps override.
+const defaultRateLimitQPS = 20.0
+
+// rateLimitQPSConfigKey is the BotRunner.Config key operators can set to
+// override the per-bot proxy rate limit.
+const rateLimitQPSConfigKey = "rate_limit_qps"
+
+// defaultCacheTTL is the freshness window for a cached GET response when
+// neither BotProxy.CacheTTL nor a CacheTTLByEndpoint entry applies.
+const defaultCacheTTL = 500 * time.Millisecond
+
+// defaultStaleIfErrorWindow bounds how long a stale cached response may be
+// served in place of a 5xx from the bot's backend.
+const defaultStaleIfErrorWindow = 30 * time.Second
+
+// cacheSweepInterval is how many cache.Store calls accumulate between
+// sweeps that evict entries past their stale-if-error window. Cache keys
+// embed the caller-controlled query string (serveCachedGET), so without a
+// sweep an authenticated caller could grow the cache unboundedly just by
+// varying it.
+const cacheSweepInterval = 256
+
+// defaultCacheTTLByEndpoint seeds BotProxy.CacheTTLByEndpoint with per-
+// endpoint overrides for the Freqtrade REST routes dashboards poll most:
+// status changes fastest and keeps the 500ms default, the rest tolerate a
+// slightly longer window.
+func defaultCacheTTLByEndpoint() map[string]time.Duration {
+	return map[string]time.Duration{
+		"/api/v1/status":    defaultCacheTTL,
+		"/api/v1/profit":    2 * time.Second,
+		"/api/v1/balance":   2 * time.Second,
+		"/api/v1/whitelist": 5 * time.Second,
+	}
+}
+
+// tokenBucket is a simple, lock-protected token-bucket rate limiter.
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+func newTokenBucket(qps float64) *tokenBucket {
+	return &tokenBucket{
+		tokens:     qps,
+		maxTokens:  qps,
+		refillRate: qps,
+		lastRefill: time.Now(),
+	}
+}
+
+// Allow reports whether a request may proceed, consuming a token if so.
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.maxTokens, b.tokens+now.Sub(b.lastRefill).Seconds()*b.refillRate)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// allowRequest enforces botID's token-bucket rate limit, creating and
+// caching the bucket (looking up any runner config override) on first use.
+func (p *BotProxy) allowRequest(ctx context.Context, botID uuid.UUID) bool {
+	key := botID.String()
+	if v, ok := p.rateLimiters.Load(key); ok {
+		return v.(*tokenBucket).Allow()
+	}
+
+	bucket := newTokenBucket(p.rateLimitQPSFor(ctx, botID))
+	actual, _ := p.rateLimiters.LoadOrStore(key, bucket)
+	return actual.(*tokenBucket).Allow()
+}
+
+// rateLimitQPSFor returns botID's configured rate limit: the runner config's
+// rate_limit_qps if set and positive, otherwise BotProxy.RateLimitQPS.
+func (p *BotProxy) rateLimitQPSFor(ctx context.Context, botID uuid.UUID) float64 {
+	qps := p.RateLimitQPS
+	if qps <= 0 {
+		qps = defaultRateLimitQPS
+	}
+
+	b, err := p.client.Bot.Query().Where(bot.ID(botID)).WithRunner().Only(ctx)
+	if err != nil || b.Edges.Runner == nil {
+		return qps
+	}
+
+	if raw, ok := b.Edges.Runner.Config[rateLimitQPSConfigKey]; ok {
+		if override, ok := toPositiveFloat(raw); ok {
+			return override
+		}
+	}
+	return qps
+}
+
+// toPositiveFloat extracts a positive float64 from a decoded JSON config
+// value (float64 or json.Number), reporting false for anything else.
+func toPositiveFloat(v interface{}) (float64, bool) {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case json.Number:
+		parsed, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	default:
+		return 0, false
+	}
+	if f <= 0 {
+		return 0, false
+	}
+	return f, true
+}
+
+// cachedResponse is a captured upstream GET response, replayed on a cache
+// hit or served stale if the upstream starts erroring.
+type cachedResponse struct {
+	status    int
+	header    http.Header
+	body      []byte
+	etag      string
+	expiresAt time.Time
+	staleAt   time.Time
+}
+
+func (c *cachedResponse) fresh(now time.Time) bool { return now.Before(c.expiresAt) }
+func (c *cachedResponse) stale(now time.Time) bool { return now.Before(c.staleAt) }
+
+// serveCachedGET serves botID's GET request to strippedPath from cache when
+// fresh, otherwise coalesces concurrent identical requests into a single
+// upstream fetch (falling back to a stale cached copy if that fetch fails).
+func (p *BotProxy) serveCachedGET(w http.ResponseWriter, r *http.Request, botIDStr string, targetURL *url.URL, strippedPath, fullPath string) {
+	cacheKey := fmt.Sprintf("%s %s?%s", botIDStr, strippedPath, r.URL.RawQuery)
+	now := time.Now()
+
+	if v, ok := p.cache.Load(cacheKey); ok {
+		if entry := v.(*cachedResponse); entry.fresh(now) {
+			p.metrics.incCacheHit(botIDStr)
+			writeCachedResponse(w, r, entry)
+			return
+		}
+	}
+
+	result, _, shared := p.group.Do(cacheKey, func() (interface{}, error) {
+		return p.fetchAndCache(r, targetURL, fullPath, strippedPath, cacheKey), nil
+	})
+	if shared {
+		p.metrics.incCoalesced(botIDStr)
+	}
+
+	writeCachedResponse(w, r, result.(*cachedResponse))
+}
+
+// fetchAndCache proxies r to the bot's backend, caching the response if it
+// isn't a server error. On a server error, it returns (and does not evict)
+// the last good cached entry if it's still within the stale-if-error window.
+func (p *BotProxy) fetchAndCache(r *http.Request, targetURL *url.URL, fullPath, strippedPath, cacheKey string) *cachedResponse {
+	proxy := p.newReverseProxy(targetURL, fullPath)
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, r)
+
+	now := time.Now()
+	body := append([]byte(nil), rec.Body.Bytes()...)
+	entry := &cachedResponse{
+		status:    rec.Code,
+		header:    rec.Header().Clone(),
+		body:      body,
+		etag:      computeETag(body),
+		expiresAt: now.Add(p.cacheTTLFor(strippedPath)),
+		staleAt:   now.Add(p.staleIfErrorWindow()),
+	}
+
+	if entry.status >= http.StatusInternalServerError {
+		if v, ok := p.cache.Load(cacheKey); ok {
+			if prev := v.(*cachedResponse); prev.stale(now) {
+				return prev
+			}
+		}
+		return entry
+	}
+
+	p.cache.Store(cacheKey, entry)
+	if atomic.AddInt64(&p.cacheStores, 1)%cacheSweepInterval == 0 {
+		p.sweepCache(now)
+	}
+	return entry
+}
+
+// sweepCache evicts every cache entry that is no longer within its
+// stale-if-error window, bounding the cache's size since it is otherwise
+// only ever added to, never cleaned up.
+func (p *BotProxy) sweepCache(now time.Time) {
+	p.cache.Range(func(key, value interface{}) bool {
+		if entry, ok := value.(*cachedResponse); ok && !entry.stale(now) {
+			p.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+// cacheTTLFor returns the cache freshness window for strippedPath.
+func (p *BotProxy) cacheTTLFor(strippedPath string) time.Duration {
+	if ttl, ok := p.CacheTTLByEndpoint[strippedPath]; ok {
+		return ttl
+	}
+	if p.CacheTTL > 0 {
+		return p.CacheTTL
+	}
+	return defaultCacheTTL
+}
+
+// staleIfErrorWindow returns BotProxy.StaleIfErrorWindow, or the default if unset.
+func (p *BotProxy) staleIfErrorWindow() time.Duration {
+	if p.StaleIfErrorWindow > 0 {
+		return p.StaleIfErrorWindow
+	}
+	return defaultStaleIfErrorWindow
+}
+
+// writeCachedResponse replays entry to w, setting Cache-Control/ETag headers
+// and answering a matching If-None-Match with 304 Not Modified.
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, entry *cachedResponse) {
+	for name, values := range entry.header {
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+
+	ttl := time.Until(entry.expiresAt)
+	if ttl < 0 {
+		ttl = 0
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(math.Ceil(ttl.Seconds()))))
+	w.Header().Set("ETag", entry.etag)
+
+	if r.Header.Get("If-None-Match") == entry.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(entry.status)
+	_, _ = w.Write(entry.body)
+}
+
+// computeETag returns a strong ETag for body.
+func computeETag(body []byte) string {
+	sum := sha1.Sum(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
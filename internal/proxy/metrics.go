@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// proxyMetrics tracks BotProxy's rate limiting, coalescing, and caching
+// counters, partitioned by bot ID so operators can size limits per tenant.
+type proxyMetrics struct {
+	mu          sync.Mutex
+	cacheHits   map[string]int64
+	coalesced   map[string]int64
+	rateLimited map[string]int64
+}
+
+func newProxyMetrics() *proxyMetrics {
+	return &proxyMetrics{
+		cacheHits:   make(map[string]int64),
+		coalesced:   make(map[string]int64),
+		rateLimited: make(map[string]int64),
+	}
+}
+
+func (m *proxyMetrics) incCacheHit(botID string)    { m.inc(m.cacheHits, botID) }
+func (m *proxyMetrics) incCoalesced(botID string)   { m.inc(m.coalesced, botID) }
+func (m *proxyMetrics) incRateLimited(botID string) { m.inc(m.rateLimited, botID) }
+
+func (m *proxyMetrics) inc(counter map[string]int64, botID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter[botID]++
+}
+
+// WriteTo renders the tracked counters in Prometheus text exposition format.
+func (m *proxyMetrics) WriteTo(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := writeCounterFamily(w, "proxy_cache_hits_total", "Total BotProxy cache hits, partitioned by bot ID.", m.cacheHits); err != nil {
+		return err
+	}
+	if err := writeCounterFamily(w, "proxy_coalesced_total", "Total BotProxy requests served by joining an in-flight upstream fetch, partitioned by bot ID.", m.coalesced); err != nil {
+		return err
+	}
+	return writeCounterFamily(w, "proxy_rate_limited_total", "Total BotProxy requests rejected by the per-bot rate limiter, partitioned by bot ID.", m.rateLimited)
+}
+
+// writeCounterFamily writes one Prometheus counter family: a HELP/TYPE
+// preamble followed by one sample line per bot ID, in sorted order so
+// output is stable across scrapes.
+func writeCounterFamily(w io.Writer, name, help string, counter map[string]int64) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name); err != nil {
+		return err
+	}
+
+	botIDs := make([]string, 0, len(counter))
+	for botID := range counter {
+		botIDs = append(botIDs, botID)
+	}
+	sort.Strings(botIDs)
+
+	for _, botID := range botIDs {
+		if _, err := fmt.Fprintf(w, "%s{bot_id=%q} %d\n", name, botID, counter[botID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetricsHandler returns an http.Handler serving the rate-limit, cache, and
+// coalescing counters in Prometheus text exposition format, for a Prometheus
+// scrape target.
+func (p *BotProxy) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = p.metrics.WriteTo(w)
+	})
+}
@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"errors"
 	"fmt"
 
 	"volaticloud/internal/enum"
@@ -37,7 +38,14 @@ func ValidateConfig(runnerType enum.RunnerType, configData map[string]interface{
 	// Extract runner-type-specific config (handle both nested and direct formats)
 	typeConfig := ExtractRunnerConfig(configData, runnerType)
 
-	// Try to get registered validator first
+	// Prefer the schema registry: it gives callers a typed *ValidationError
+	// and lazily upgrades old payloads via Migrate before validating.
+	if _, err := ValidateConfigSchema(runnerType, typeConfig); !errors.Is(err, ErrNoSchemaRegistered) {
+		return err
+	}
+
+	// Fall back to a legacy registered validator for runner types that
+	// haven't been migrated to the schema registry yet.
 	validator, err := GetConfigValidator(runnerType)
 	if err == nil {
 		return validator(typeConfig)
@@ -241,18 +241,13 @@ Benefits:
  6. Cleanup container and volume
  7. Return BacktestResult with parsed data
 
-## KubernetesRuntime (Stub)
+## KubernetesRuntime
 
-Kubernetes implementation for production clusters:
-
-	Planned Features:
-	  - Pod lifecycle management
-	  - Deployment/StatefulSet support
-	  - ConfigMap for configs
-	  - Secret for credentials
-	  - PVC for data volumes
-	  - Service for API access
-	  - Resource quotas and limits
+The Kubernetes Runtime implementation lives in internal/kubernetes (not in
+this package, unlike DockerRuntime/LocalRuntime) and registers itself with
+this package's runtime registry via RegisterRuntimeCreator in its init().
+See internal/kubernetes/runtime.go and internal/kubernetes/doc.go for the
+Pod/Deployment/Service lifecycle it manages.
 
 ## LocalRuntime (Stub)
 
@@ -558,7 +553,7 @@ The package provides MockRuntime for testing without Docker:
 All implementations verify interface compliance at compile time:
 
 	var _ Runtime = (*DockerRuntime)(nil)
-	var _ Runtime = (*KubernetesRuntime)(nil)
+	var _ Runtime = (*kubernetes.Runtime)(nil)
 	var _ Runtime = (*LocalRuntime)(nil)
 	var _ BacktestRunner = (*DockerBacktest)(nil)
 
@@ -697,7 +692,6 @@ Factory tests verify runtime creation:
 	factory.go             - Runtime factory implementation
 	docker_runner.go       - Docker SDK implementation
 	docker_backtest.go     - Docker backtest implementation
-	kubernetes.go          - Kubernetes implementation (stub)
 	local.go               - Local process implementation (stub)
 	types.go               - Core types (BotSpec, BotStatus, etc.)
 	backtest_types.go      - Backtest-specific types
@@ -20,6 +20,11 @@ func (f *Factory) Create(ctx context.Context, runnerType enum.RunnerType, config
 	// Extract runner-type-specific config
 	typeConfig := ExtractRunnerConfig(configData, runnerType)
 
+	// Lazily upgrade configs stored under an older schema version before use.
+	if migrated, err := MigrateConfigSchema(runnerType, typeConfig); err == nil {
+		typeConfig = migrated
+	}
+
 	// Try to get registered creator first
 	creator, err := GetRuntimeCreator(runnerType)
 	if err == nil {
@@ -41,6 +46,11 @@ func (f *Factory) CreateBacktestRunner(ctx context.Context, runnerType enum.Runn
 	// Extract runner-type-specific config
 	typeConfig := ExtractRunnerConfig(configData, runnerType)
 
+	// Lazily upgrade configs stored under an older schema version before use.
+	if migrated, err := MigrateConfigSchema(runnerType, typeConfig); err == nil {
+		typeConfig = migrated
+	}
+
 	// Try to get registered creator first
 	creator, err := GetBacktestRunnerCreator(runnerType)
 	if err == nil {
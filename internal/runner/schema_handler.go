@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"volaticloud/internal/enum"
+)
+
+// SchemaHandler returns an http.Handler that serves the JSON Schema for a
+// runner type so admin UIs can render config forms.
+// URL pattern: /admin/runner-schemas/{type}?version=N (version defaults to
+// the latest registered version).
+func SchemaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runnerType := enum.RunnerType(chi.URLParam(r, "type"))
+
+		version, ok := parseVersionParam(r, runnerType)
+		if !ok {
+			http.Error(w, "no schema registered for runner type: "+string(runnerType), http.StatusNotFound)
+			return
+		}
+
+		schemaJSON, ok := ConfigSchema(runnerType, version)
+		if !ok {
+			http.Error(w, "no schema registered for "+string(runnerType)+" version "+strconv.Itoa(version), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(schemaJSON)
+	})
+}
+
+// parseVersionParam resolves the requested schema version, falling back to
+// the latest registered version when the query param is absent or invalid.
+func parseVersionParam(r *http.Request, runnerType enum.RunnerType) (int, bool) {
+	if raw := r.URL.Query().Get("version"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil {
+			return v, true
+		}
+	}
+	return LatestConfigSchemaVersion(runnerType)
+}
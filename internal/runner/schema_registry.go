@@ -0,0 +1,257 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"volaticloud/internal/enum"
+)
+
+// ErrNoSchemaRegistered is returned by SchemaRegistry.Validate when no JSON
+// Schema has been registered for a runner type. Callers (ValidateConfig) use
+// this to fall back to the legacy GetConfigValidator path for runner types
+// that haven't been migrated to the schema registry yet.
+var ErrNoSchemaRegistered = errors.New("no schema registered for runner type")
+
+// FieldError describes a single JSON Schema validation failure.
+type FieldError struct {
+	// Field is the dot-separated path into the config payload, e.g. "host"
+	// or "defaultResources.cpuRequest".
+	Field string
+
+	// Reason is the human-readable description of what failed.
+	Reason string
+}
+
+// ValidationError is returned by SchemaRegistry.Validate (and surfaces
+// through ValidateConfig) when a config payload fails schema validation. It
+// carries the individual field failures so UIs can highlight the offending
+// fields instead of parsing a single error string.
+type ValidationError struct {
+	RunnerType enum.RunnerType
+	Version    int
+	Fields     []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = fmt.Sprintf("%s: %s", f.Field, f.Reason)
+	}
+	return fmt.Sprintf("%s config v%d validation failed: %s", e.RunnerType, e.Version, strings.Join(parts, "; "))
+}
+
+// MigrationStep upgrades a config payload from one schema version to the
+// next. It should only transform data; SchemaRegistry.Migrate stamps the
+// resulting "version" field itself.
+type MigrationStep func(payload map[string]interface{}) (map[string]interface{}, error)
+
+type schemaEntry struct {
+	raw      []byte
+	compiled *gojsonschema.Schema
+}
+
+// SchemaRegistry stores a JSON Schema (draft-2020-12) per (RunnerType,
+// version) pair plus the MigrationSteps needed to lazily upgrade older
+// payloads read from the database. It replaces the flat GetConfigValidator
+// map for runner types that have been migrated to schema-based validation.
+type SchemaRegistry struct {
+	mu         sync.RWMutex
+	schemas    map[enum.RunnerType]map[int]schemaEntry
+	migrations map[enum.RunnerType]map[int]MigrationStep
+}
+
+// NewSchemaRegistry creates an empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas:    make(map[enum.RunnerType]map[int]schemaEntry),
+		migrations: make(map[enum.RunnerType]map[int]MigrationStep),
+	}
+}
+
+// RegisterSchema compiles schemaJSON and registers it as runnerType's schema
+// for version. schemaJSON is expected to declare
+// "$schema": "https://json-schema.org/draft/2020-12/schema".
+func (r *SchemaRegistry) RegisterSchema(runnerType enum.RunnerType, version int, schemaJSON []byte) error {
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaJSON))
+	if err != nil {
+		return fmt.Errorf("failed to compile schema for %s v%d: %w", runnerType, version, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas[runnerType] == nil {
+		r.schemas[runnerType] = make(map[int]schemaEntry)
+	}
+	r.schemas[runnerType][version] = schemaEntry{raw: schemaJSON, compiled: compiled}
+	return nil
+}
+
+// RegisterMigration registers the step that upgrades runnerType's config
+// payload from fromVersion to fromVersion+1. SchemaRegistry.Migrate walks
+// these steps in order until no further migration is registered.
+func (r *SchemaRegistry) RegisterMigration(runnerType enum.RunnerType, fromVersion int, step MigrationStep) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.migrations[runnerType] == nil {
+		r.migrations[runnerType] = make(map[int]MigrationStep)
+	}
+	r.migrations[runnerType][fromVersion] = step
+}
+
+// LatestVersion returns the highest schema version registered for
+// runnerType, and false if none is registered.
+func (r *SchemaRegistry) LatestVersion(runnerType enum.RunnerType) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions, ok := r.schemas[runnerType]
+	if !ok || len(versions) == 0 {
+		return 0, false
+	}
+
+	latest := 0
+	for v := range versions {
+		if v > latest {
+			latest = v
+		}
+	}
+	return latest, true
+}
+
+// Schema returns the raw JSON Schema document registered for
+// (runnerType, version), for rendering in the admin API.
+func (r *SchemaRegistry) Schema(runnerType enum.RunnerType, version int) ([]byte, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.schemas[runnerType][version]
+	if !ok {
+		return nil, false
+	}
+	return entry.raw, true
+}
+
+// payloadVersion reads the "version" field from a config payload, defaulting
+// to 1 for payloads predating the schema registry (stored in the DB before
+// this field existed).
+func payloadVersion(payload map[string]interface{}) int {
+	switch v := payload["version"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 1
+	}
+}
+
+// Migrate lazily upgrades payload to the newest version for which a
+// migration chain exists, applying registered MigrationSteps one version at
+// a time. A payload with no migration registered for its current version is
+// returned unchanged (it is already current, or this runner type has no
+// migrations at all).
+func (r *SchemaRegistry) Migrate(runnerType enum.RunnerType, payload map[string]interface{}) (map[string]interface{}, error) {
+	current := payload
+	version := payloadVersion(current)
+
+	for {
+		r.mu.RLock()
+		step, ok := r.migrations[runnerType][version]
+		r.mu.RUnlock()
+		if !ok {
+			break
+		}
+
+		next, err := step(current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate %s config from v%d: %w", runnerType, version, err)
+		}
+
+		version++
+		next["version"] = version
+		current = next
+	}
+
+	return current, nil
+}
+
+// Validate migrates payload to its newest known version, then validates it
+// against that version's JSON Schema. It returns ErrNoSchemaRegistered if
+// runnerType has no schema registered, so callers can fall back to a legacy
+// validator. On a structural failure it returns *ValidationError.
+func (r *SchemaRegistry) Validate(runnerType enum.RunnerType, payload map[string]interface{}) (map[string]interface{}, error) {
+	migrated, err := r.Migrate(runnerType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	version := payloadVersion(migrated)
+
+	r.mu.RLock()
+	entry, ok := r.schemas[runnerType][version]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrNoSchemaRegistered
+	}
+
+	result, err := entry.compiled.Validate(gojsonschema.NewGoLoader(migrated))
+	if err != nil {
+		return nil, fmt.Errorf("%s config v%d schema validation failed: %w", runnerType, version, err)
+	}
+
+	if !result.Valid() {
+		fields := make([]FieldError, 0, len(result.Errors()))
+		for _, desc := range result.Errors() {
+			fields = append(fields, FieldError{Field: desc.Field(), Reason: desc.Description()})
+		}
+		return nil, &ValidationError{RunnerType: runnerType, Version: version, Fields: fields}
+	}
+
+	return migrated, nil
+}
+
+// defaultSchemaRegistry is the process-wide registry used by
+// RegisterConfigSchema/RegisterConfigMigration/ValidateConfigSchema, mirroring
+// the package-level configValidators map in registry.go.
+var defaultSchemaRegistry = NewSchemaRegistry()
+
+// RegisterConfigSchema registers runnerType's JSON Schema for version on the
+// default registry. Runner packages call this from their init(), the same
+// way they call RegisterConfigValidator.
+func RegisterConfigSchema(runnerType enum.RunnerType, version int, schemaJSON []byte) error {
+	return defaultSchemaRegistry.RegisterSchema(runnerType, version, schemaJSON)
+}
+
+// RegisterConfigMigration registers a migration step on the default registry.
+func RegisterConfigMigration(runnerType enum.RunnerType, fromVersion int, step MigrationStep) {
+	defaultSchemaRegistry.RegisterMigration(runnerType, fromVersion, step)
+}
+
+// ValidateConfigSchema validates payload against the default registry. It
+// returns ErrNoSchemaRegistered for runner types with no registered schema.
+func ValidateConfigSchema(runnerType enum.RunnerType, payload map[string]interface{}) (map[string]interface{}, error) {
+	return defaultSchemaRegistry.Validate(runnerType, payload)
+}
+
+// MigrateConfigSchema upgrades payload to the newest version known to the
+// default registry without validating it.
+func MigrateConfigSchema(runnerType enum.RunnerType, payload map[string]interface{}) (map[string]interface{}, error) {
+	return defaultSchemaRegistry.Migrate(runnerType, payload)
+}
+
+// ConfigSchema returns the raw JSON Schema document for (runnerType,
+// version) from the default registry, for the admin schema endpoint.
+func ConfigSchema(runnerType enum.RunnerType, version int) ([]byte, bool) {
+	return defaultSchemaRegistry.Schema(runnerType, version)
+}
+
+// LatestConfigSchemaVersion returns the newest schema version registered for
+// runnerType on the default registry.
+func LatestConfigSchemaVersion(runnerType enum.RunnerType) (int, bool) {
+	return defaultSchemaRegistry.LatestVersion(runnerType)
+}
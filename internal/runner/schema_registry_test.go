@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/enum"
+)
+
+const testSchemaV1 = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"version": { "type": "integer", "const": 1 },
+		"host": { "type": "string", "minLength": 1 }
+	},
+	"required": ["host"]
+}`
+
+const testSchemaV2 = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"version": { "type": "integer", "const": 2 },
+		"endpoint": { "type": "string", "minLength": 1 }
+	},
+	"required": ["endpoint"]
+}`
+
+func TestSchemaRegistry_ValidateAgainstRegisteredVersion(t *testing.T) {
+	r := NewSchemaRegistry()
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 1, []byte(testSchemaV1)))
+
+	_, err := r.Validate(enum.RunnerDocker, map[string]interface{}{"host": "tcp://localhost:2375"})
+	assert.NoError(t, err)
+}
+
+func TestSchemaRegistry_ValidateReturnsFieldErrors(t *testing.T) {
+	r := NewSchemaRegistry()
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 1, []byte(testSchemaV1)))
+
+	_, err := r.Validate(enum.RunnerDocker, map[string]interface{}{})
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Equal(t, enum.RunnerDocker, valErr.RunnerType)
+	assert.Equal(t, 1, valErr.Version)
+	require.Len(t, valErr.Fields, 1)
+	assert.Equal(t, "(root)", valErr.Fields[0].Field)
+}
+
+func TestSchemaRegistry_NoSchemaRegistered(t *testing.T) {
+	r := NewSchemaRegistry()
+
+	_, err := r.Validate(enum.RunnerKubernetes, map[string]interface{}{"namespace": "default"})
+	assert.ErrorIs(t, err, ErrNoSchemaRegistered)
+}
+
+func TestSchemaRegistry_MigrateUpgradesOldPayload(t *testing.T) {
+	r := NewSchemaRegistry()
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 1, []byte(testSchemaV1)))
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 2, []byte(testSchemaV2)))
+	r.RegisterMigration(enum.RunnerDocker, 1, func(payload map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"endpoint": payload["host"]}, nil
+	})
+
+	migrated, err := r.Migrate(enum.RunnerDocker, map[string]interface{}{"host": "tcp://localhost:2375"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, migrated["version"])
+	assert.Equal(t, "tcp://localhost:2375", migrated["endpoint"])
+
+	_, err = r.Validate(enum.RunnerDocker, map[string]interface{}{"host": "tcp://localhost:2375"})
+	assert.NoError(t, err, "validating a v1 payload should migrate it to v2 before checking the schema")
+}
+
+func TestSchemaRegistry_MigrateLeavesCurrentPayloadUnchanged(t *testing.T) {
+	r := NewSchemaRegistry()
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 1, []byte(testSchemaV1)))
+
+	payload := map[string]interface{}{"host": "tcp://localhost:2375"}
+	migrated, err := r.Migrate(enum.RunnerDocker, payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload["host"], migrated["host"])
+}
+
+func TestSchemaRegistry_SchemaAndLatestVersion(t *testing.T) {
+	r := NewSchemaRegistry()
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 1, []byte(testSchemaV1)))
+	require.NoError(t, r.RegisterSchema(enum.RunnerDocker, 2, []byte(testSchemaV2)))
+
+	latest, ok := r.LatestVersion(enum.RunnerDocker)
+	require.True(t, ok)
+	assert.Equal(t, 2, latest)
+
+	raw, ok := r.Schema(enum.RunnerDocker, 1)
+	require.True(t, ok)
+	assert.Contains(t, string(raw), `"const": 1`)
+
+	_, ok = r.Schema(enum.RunnerDocker, 99)
+	assert.False(t, ok)
+}
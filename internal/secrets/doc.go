@@ -30,4 +30,42 @@
 //	Exchange.config:    exchange.key, exchange.secret, exchange.password, exchange.private_key
 //	BotRunner.config:   docker.certPEM, docker.keyPEM, docker.caPEM
 //	BotRunner.s3_config: accessKeyId, secretAccessKey
+//
+// # Envelope Encryption
+//
+// InitEnvelope configures an alternative backend where every write generates
+// a random per-record DEK, encrypts the plaintext under it, and wraps the DEK
+// with a pluggable KeyProvider (KMS) rather than a long-lived key held in
+// process memory. Values are stored as a "$vc_enc$v2$" envelope carrying
+// {kekID, wrappedDEK, nonce, ciphertext, alg, version}. Rotation then becomes
+// a KEK-only operation — RewrapFields/RewrapAll rewrap the DEK without ever
+// touching ciphertext or exposing plaintext outside the KMS.
+//
+// Rotating to a new KEK means reconfiguring DefaultEncryptor's KeyProvider
+// so its CurrentKEKID is the new key while it still retains the old one —
+// LocalKeyProvider/GCPKMSProvider accept retired keys alongside the current
+// one and fall back to them in Unwrap, and AWSKMSProvider's Unwrap lets AWS
+// KMS resolve the originating key from the ciphertext, so rows not yet
+// rewrapped keep decrypting during the migration. Dropping the old key
+// before every row is rewrapped strands the data still under it.
+//
+// RewrapAll loads every row into memory up front, which is fine for a
+// one-off rotation on a small table but races a concurrent writer: its
+// read-modify-write has no guard, so a write landing between RewrapAll's
+// read and save is silently overwritten. MigrateEntity is the safe-for-production
+// alternative: it pages through one entity type at a time and guards each
+// write with an optimistic-concurrency check on updated_at, retrying
+// conflicted rows with a bounded, jittered backoff instead of clobbering
+// them. It's also exposed as `volaticloud secrets rotate --dry-run`.
+//
+// # Garbage Collection
+//
+// GarbageCollector scrubs encrypted secret material that has outlived its
+// usefulness, per an entity-specific RetentionPolicy: once a soft-deleted
+// Exchange or Bot has sat past its grace period, the row's encrypted
+// config/secure_config is cleared so old ciphertext can't be decrypted even
+// by a restore. NewLeaderElectedGarbageCollector runs it on a schedule
+// guarded by a usage.LeaseStore lease, so only one control-plane replica GCs
+// at a time — the same lease abstraction usage.LeaderElectedAggregator
+// uses, rather than a second leader-election mechanism.
 package secrets
@@ -15,13 +15,31 @@ const (
 	encV1Prefix = "$vc_enc$v1$"
 )
 
+// Encryptor encrypts and decrypts individual string values. Implementations
+// decide how keys are sourced and rotated; callers only see opaque,
+// self-describing ciphertext strings.
+type Encryptor interface {
+	// Encrypt returns an opaque, versioned ciphertext string for plaintext.
+	Encrypt(plaintext string) (string, error)
+	// Decrypt reverses Encrypt. It must accept every ciphertext format this
+	// package has ever produced.
+	Decrypt(value string) (string, error)
+	// Rewrap re-encrypts value under the current primary key/KEK without
+	// requiring the caller to handle plaintext. It is a no-op cost-wise for
+	// envelope-based implementations that only need to rewrap the DEK.
+	Rewrap(value string) (string, error)
+}
+
 // DefaultEncryptor is the singleton encryptor initialized at startup.
-var DefaultEncryptor *Encryptor
+var DefaultEncryptor Encryptor
 
 // Init initializes the default encryptor with a base64-encoded 32-byte AES key.
 // Additional old keys can be provided for key rotation — they will be tried
 // during decryption if the primary key fails.
 // Returns an error if any key is invalid. If currentKeyBase64 is empty, encryption is disabled.
+//
+// This configures the legacy single-KEK backend. For envelope encryption with
+// a pluggable KMS, use InitEnvelope instead.
 func Init(currentKeyBase64 string, oldKeysBase64 ...string) error {
 	if currentKeyBase64 == "" {
 		DefaultEncryptor = nil
@@ -45,10 +63,21 @@ func Init(currentKeyBase64 string, oldKeysBase64 ...string) error {
 		oldKeys = append(oldKeys, k)
 	}
 
-	DefaultEncryptor = &Encryptor{primaryKey: primaryKey, oldKeys: oldKeys}
+	DefaultEncryptor = &staticEncryptor{primaryKey: primaryKey, oldKeys: oldKeys}
 	return nil
 }
 
+// InitEnvelope initializes the default encryptor with envelope encryption
+// backed by a pluggable KeyProvider (KMS). Every write generates a random
+// per-record DEK, wraps it with the provider's current KEK, and stores
+// {kekID, wrappedDEK, nonce, ciphertext, alg, version} as a self-describing
+// envelope. fallback, if non-nil, is consulted for values produced by the
+// legacy single-KEK Encryptor (Init) so existing ciphertext keeps decrypting
+// during migration.
+func InitEnvelope(provider KeyProvider, fallback Encryptor) {
+	DefaultEncryptor = &envelopeEncryptor{provider: provider, fallback: fallback}
+}
+
 // decodeKey decodes and validates a base64-encoded 32-byte AES key.
 func decodeKey(keyBase64 string) ([]byte, error) {
 	key, err := base64.StdEncoding.DecodeString(keyBase64)
@@ -66,14 +95,16 @@ func Enabled() bool {
 	return DefaultEncryptor != nil
 }
 
-// Encryptor performs AES-256-GCM encryption and decryption.
-type Encryptor struct {
+// staticEncryptor performs AES-256-GCM encryption and decryption under a
+// single, long-lived KEK held in process memory. This is the legacy backend
+// configured via Init; prefer InitEnvelope for new deployments.
+type staticEncryptor struct {
 	primaryKey []byte
 	oldKeys    [][]byte
 }
 
 // Encrypt encrypts plaintext using the primary key and returns "$vc_enc$v1$<base64(nonce|ciphertext)>".
-func (e *Encryptor) Encrypt(plaintext string) (string, error) {
+func (e *staticEncryptor) Encrypt(plaintext string) (string, error) {
 	block, err := aes.NewCipher(e.primaryKey)
 	if err != nil {
 		return "", fmt.Errorf("secrets: cipher error: %w", err)
@@ -96,7 +127,7 @@ func (e *Encryptor) Encrypt(plaintext string) (string, error) {
 // Decrypt decrypts a value produced by Encrypt. It supports both the old format
 // ($vc_enc$<base64>) and the versioned format ($vc_enc$v1$<base64>).
 // On GCM auth failure with the primary key, it tries each old key before returning an error.
-func (e *Encryptor) Decrypt(value string) (string, error) {
+func (e *staticEncryptor) Decrypt(value string) (string, error) {
 	if !strings.HasPrefix(value, encPrefix) {
 		return "", fmt.Errorf("secrets: value does not have encryption prefix")
 	}
@@ -126,6 +157,16 @@ func (e *Encryptor) Decrypt(value string) (string, error) {
 	return "", fmt.Errorf("secrets: decryption failed with all keys")
 }
 
+// Rewrap re-encrypts value under the primary key. For the static backend this
+// means a full decrypt/re-encrypt, since there is no separate DEK to rewrap.
+func (e *staticEncryptor) Rewrap(value string) (string, error) {
+	plaintext, err := e.Decrypt(value)
+	if err != nil {
+		return "", fmt.Errorf("secrets: rewrap: %w", err)
+	}
+	return e.Encrypt(plaintext)
+}
+
 // decryptWithKey attempts to decrypt data with a single AES-256-GCM key.
 func decryptWithKey(key, data []byte) (string, error) {
 	block, err := aes.NewCipher(key)
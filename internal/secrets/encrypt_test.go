@@ -59,7 +59,7 @@ func TestInit(t *testing.T) {
 		err := Init(testKey(), testKeyB())
 		require.NoError(t, err)
 		assert.True(t, Enabled())
-		assert.Len(t, DefaultEncryptor.oldKeys, 1)
+		assert.Len(t, DefaultEncryptor.(*staticEncryptor).oldKeys, 1)
 	})
 
 	t.Run("invalid old key", func(t *testing.T) {
@@ -71,7 +71,7 @@ func TestInit(t *testing.T) {
 	t.Run("empty old key skipped", func(t *testing.T) {
 		err := Init(testKey(), "", testKeyB())
 		require.NoError(t, err)
-		assert.Len(t, DefaultEncryptor.oldKeys, 1)
+		assert.Len(t, DefaultEncryptor.(*staticEncryptor).oldKeys, 1)
 	})
 }
 
@@ -195,7 +195,7 @@ func TestDecryptWithWrongKey(t *testing.T) {
 	for i := range otherKey {
 		otherKey[i] = byte(i + 100)
 	}
-	other := &Encryptor{primaryKey: otherKey}
+	other := &staticEncryptor{primaryKey: otherKey}
 
 	_, err = other.Decrypt(encrypted)
 	assert.Error(t, err)
@@ -0,0 +1,197 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	envV2Prefix  = "$vc_enc$v2$"
+	envelopeAlg  = "AES-256-GCM"
+	envelopeVers = 2
+)
+
+// envelope is the self-describing, versioned payload stored for every
+// envelope-encrypted field. It is serialized as base64-encoded JSON so it
+// round-trips through the same plain string columns the legacy format uses.
+type envelope struct {
+	KEKID      string `json:"kekID"`
+	WrappedDEK []byte `json:"wrappedDEK"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+	Alg        string `json:"alg"`
+	Version    int    `json:"version"`
+}
+
+// envelopeEncryptor implements Encryptor with per-record DEKs wrapped by a
+// pluggable KeyProvider (KMS). Plaintext never needs to leave the process to
+// be re-keyed: rotation only rewraps the DEK, it never touches ciphertext.
+type envelopeEncryptor struct {
+	provider KeyProvider
+	// fallback decrypts ciphertext produced by an older Encryptor
+	// (typically the legacy staticEncryptor) so migration to envelope
+	// encryption doesn't require re-encrypting everything up front.
+	fallback Encryptor
+}
+
+// NewEnvelopeEncryptor builds an Encryptor that performs envelope encryption
+// against the given KeyProvider. fallback may be nil.
+func NewEnvelopeEncryptor(provider KeyProvider, fallback Encryptor) Encryptor {
+	return &envelopeEncryptor{provider: provider, fallback: fallback}
+}
+
+// CurrentKEKID returns the KEK identifier new envelopes are wrapped under.
+// RewrapAll uses this to confirm the encryptor is already configured for the
+// target key before rewrapping every stored envelope.
+func (e *envelopeEncryptor) CurrentKEKID() string {
+	return e.provider.CurrentKEKID()
+}
+
+func (e *envelopeEncryptor) Encrypt(plaintext string) (string, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", fmt.Errorf("secrets: DEK generation error: %w", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("secrets: nonce generation error: %w", err)
+	}
+	ciphertext := gcm.Seal(nil, nonce, []byte(plaintext), nil)
+
+	wrapped, err := e.provider.Wrap(context.Background(), dek)
+	if err != nil {
+		return "", fmt.Errorf("secrets: wrap DEK: %w", err)
+	}
+
+	env := envelope{
+		KEKID:      e.provider.CurrentKEKID(),
+		WrappedDEK: wrapped,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+		Alg:        envelopeAlg,
+		Version:    envelopeVers,
+	}
+	return encodeEnvelope(env)
+}
+
+func (e *envelopeEncryptor) Decrypt(value string) (string, error) {
+	if !isEnvelopeFormat(value) {
+		if e.fallback != nil {
+			return e.fallback.Decrypt(value)
+		}
+		return "", fmt.Errorf("secrets: value is not an envelope and no fallback Encryptor is configured")
+	}
+
+	env, err := decodeEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+
+	dek, err := e.provider.Unwrap(context.Background(), env.WrappedDEK)
+	if err != nil {
+		return "", fmt.Errorf("secrets: unwrap DEK: %w", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("secrets: envelope authentication failed: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap moves value's DEK under the provider's current KEK without ever
+// decrypting the ciphertext itself — the defining property of envelope
+// rotation. Values in the legacy (pre-envelope) format are re-encrypted in
+// full via the fallback Encryptor, since they have no DEK to rewrap.
+func (e *envelopeEncryptor) Rewrap(value string) (string, error) {
+	if !isEnvelopeFormat(value) {
+		if e.fallback == nil {
+			return "", fmt.Errorf("secrets: value is not an envelope and no fallback Encryptor is configured")
+		}
+		plaintext, err := e.fallback.Decrypt(value)
+		if err != nil {
+			return "", err
+		}
+		return e.Encrypt(plaintext)
+	}
+
+	env, err := decodeEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	if env.KEKID == e.provider.CurrentKEKID() {
+		return value, nil // already under the current KEK, nothing to do
+	}
+
+	dek, err := e.provider.Unwrap(context.Background(), env.WrappedDEK)
+	if err != nil {
+		return "", fmt.Errorf("secrets: rewrap: unwrap DEK: %w", err)
+	}
+	wrapped, err := e.provider.Wrap(context.Background(), dek)
+	if err != nil {
+		return "", fmt.Errorf("secrets: rewrap: wrap DEK: %w", err)
+	}
+
+	env.KEKID = e.provider.CurrentKEKID()
+	env.WrappedDEK = wrapped
+	return encodeEnvelope(env)
+}
+
+func isEnvelopeFormat(value string) bool {
+	return len(value) >= len(envV2Prefix) && value[:len(envV2Prefix)] == envV2Prefix
+}
+
+func encodeEnvelope(env envelope) (string, error) {
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return "", fmt.Errorf("secrets: marshal envelope: %w", err)
+	}
+	return envV2Prefix + base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func decodeEnvelope(value string) (envelope, error) {
+	raw, err := base64.StdEncoding.DecodeString(value[len(envV2Prefix):])
+	if err != nil {
+		return envelope{}, fmt.Errorf("secrets: invalid envelope base64: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return envelope{}, fmt.Errorf("secrets: invalid envelope JSON: %w", err)
+	}
+	return env, nil
+}
+
+// RewrapFields rewraps every already-encrypted field in config at the given
+// dot-paths under the current primary key/KEK, without exposing plaintext to
+// the caller. Plaintext and unencrypted fields are left untouched.
+func RewrapFields(config map[string]interface{}, paths []string) error {
+	if !Enabled() || config == nil {
+		return nil
+	}
+	for _, path := range paths {
+		if err := transformField(config, path, rewrap); err != nil {
+			return fmt.Errorf("rewrap field %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func rewrap(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil // plaintext passthrough, nothing to rewrap
+	}
+	return DefaultEncryptor.Rewrap(value)
+}
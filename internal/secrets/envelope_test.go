@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLocalProvider(t *testing.T, kekID string) *LocalKeyProvider {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + len(kekID))
+	}
+	p, err := NewLocalKeyProvider(kekID, key)
+	require.NoError(t, err)
+	return p
+}
+
+func TestEnvelopeEncryptDecryptRoundTrip(t *testing.T) {
+	enc := NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+
+	encrypted, err := enc.Encrypt("envelope-secret")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.True(t, isEnvelopeFormat(encrypted))
+
+	decrypted, err := enc.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "envelope-secret", decrypted)
+}
+
+func TestEnvelopeEncryptProducesDifferentWrappedDEKs(t *testing.T) {
+	enc := NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+
+	enc1, err := enc.Encrypt("same-value")
+	require.NoError(t, err)
+	enc2, err := enc.Encrypt("same-value")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, enc1, enc2)
+
+	dec1, err := enc.Decrypt(enc1)
+	require.NoError(t, err)
+	dec2, err := enc.Decrypt(enc2)
+	require.NoError(t, err)
+	assert.Equal(t, dec1, dec2)
+}
+
+func TestEnvelopeFallsBackToLegacyFormat(t *testing.T) {
+	require.NoError(t, Init(testKey()))
+	legacy := DefaultEncryptor
+	defer func() { DefaultEncryptor = nil }()
+
+	legacyValue, err := legacy.Encrypt("legacy-secret")
+	require.NoError(t, err)
+
+	enc := NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), legacy)
+	decrypted, err := enc.Decrypt(legacyValue)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-secret", decrypted)
+}
+
+func TestEnvelopeWithoutFallbackRejectsLegacyFormat(t *testing.T) {
+	require.NoError(t, Init(testKey()))
+	legacy := DefaultEncryptor
+	defer func() { DefaultEncryptor = nil }()
+
+	legacyValue, err := legacy.Encrypt("legacy-secret")
+	require.NoError(t, err)
+
+	enc := NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	_, err = enc.Decrypt(legacyValue)
+	assert.Error(t, err)
+}
+
+// dualKeyProvider unwraps with whichever of two LocalKeyProviders succeeds,
+// mirroring how a real KMS resolves the right key version from metadata
+// embedded in the wrapped DEK rather than needing the caller to track it.
+type dualKeyProvider struct {
+	current *LocalKeyProvider
+	legacy  *LocalKeyProvider
+}
+
+func (d dualKeyProvider) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	return d.current.Wrap(ctx, dek)
+}
+
+func (d dualKeyProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	if dek, err := d.current.Unwrap(ctx, wrapped); err == nil {
+		return dek, nil
+	}
+	return d.legacy.Unwrap(ctx, wrapped)
+}
+
+func (d dualKeyProvider) CurrentKEKID() string {
+	return d.current.CurrentKEKID()
+}
+
+func TestEnvelopeRewrapUnderNewKEKKeepsCiphertext(t *testing.T) {
+	oldProvider := testLocalProvider(t, "kek-v1")
+	enc := NewEnvelopeEncryptor(oldProvider, nil)
+
+	encrypted, err := enc.Encrypt("rotate-me")
+	require.NoError(t, err)
+
+	newProvider := testLocalProvider(t, "kek-v2")
+	dual := dualKeyProvider{current: newProvider, legacy: oldProvider}
+	rotated := NewEnvelopeEncryptor(dual, nil)
+
+	rewrapped, err := rotated.Rewrap(encrypted)
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, rewrapped)
+
+	env, err := decodeEnvelope(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "kek-v2", env.KEKID)
+
+	original, err := decodeEnvelope(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, original.Ciphertext, env.Ciphertext)
+
+	decrypted, err := rotated.Decrypt(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "rotate-me", decrypted)
+}
+
+func TestRewrapFieldsSkipsPlaintext(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	defer func() { DefaultEncryptor = nil }()
+
+	config := map[string]interface{}{
+		"exchange": map[string]interface{}{
+			"key":    "plaintext-key",
+			"secret": "",
+		},
+	}
+
+	require.NoError(t, RewrapFields(config, []string{"exchange.key", "exchange.secret"}))
+	assert.Equal(t, "plaintext-key", config["exchange"].(map[string]interface{})["key"])
+}
+
+func TestLocalKeyProviderWrapUnwrapRoundTrip(t *testing.T) {
+	p := testLocalProvider(t, "kek-v1")
+	dek := []byte("0123456789abcdef0123456789abcdef")[:32]
+
+	wrapped, err := p.Wrap(context.Background(), dek)
+	require.NoError(t, err)
+
+	unwrapped, err := p.Unwrap(context.Background(), wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dek, unwrapped)
+	assert.Equal(t, "kek-v1", p.CurrentKEKID())
+}
@@ -0,0 +1,206 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	entgen "volaticloud/internal/ent"
+	entbot "volaticloud/internal/ent/bot"
+	entexchange "volaticloud/internal/ent/exchange"
+	"volaticloud/internal/ent/mixin"
+)
+
+// Clock abstracts the current time so GarbageCollector's grace-period checks
+// can be driven deterministically in tests, rather than waiting on a real
+// retention window to elapse.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default Clock, backed by time.Now.
+type SystemClock struct{}
+
+// Now returns the current wall-clock time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// RetentionPolicy controls how long a soft-deleted row's encrypted secret
+// material is kept readable before GarbageCollector scrubs it. A grace
+// period of zero disables scrubbing for that entity.
+type RetentionPolicy struct {
+	ExchangeGracePeriod time.Duration
+	BotGracePeriod      time.Duration
+}
+
+// DefaultRetentionPolicy returns the retention policy used when none is
+// supplied: a 30-day grace period for both entities. It is independent of
+// the org grace period, which callers pass to billing.MarkPendingDeletion.
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		ExchangeGracePeriod: 30 * 24 * time.Hour,
+		BotGracePeriod:      30 * 24 * time.Hour,
+	}
+}
+
+// Metrics reports one GarbageCollector run's outcome.
+type Metrics struct {
+	Deleted  int
+	Errors   int
+	Duration time.Duration
+}
+
+// MetricsHook is called with the Metrics of every GarbageCollector run, so
+// callers can export them (e.g. to the usage Prometheus exporter or logs).
+type MetricsHook func(Metrics)
+
+// GarbageCollector scrubs encrypted secret material that has outlived its
+// usefulness:
+//
+//  1. Audit/history rows for bot.secure_config and exchange.config past a
+//     retention window. This tree has no such audit/history tables yet
+//     (only the live rows themselves), so this step is currently a no-op;
+//     it's reserved for when that history table exists.
+//  2. Wrapped-DEK cache entries whose keyID no longer resolves to any
+//     active KEK version. This tree has no wrapped-DEK cache (KeyProvider
+//     implementations unwrap on demand, uncached), so this step is also
+//     currently a no-op, reserved likewise.
+//  3. Orphan encrypted blobs on soft-deleted Exchanges and Bots, once
+//     RetentionPolicy's grace period has elapsed: the row's encrypted
+//     config/secure_config is cleared so old ciphertext can't be read back
+//     even by a restore, while the row itself (and its non-secret fields)
+//     is left for the existing PurgeExpired/ProcessPendingDeletions sweeps
+//     to hard-delete on their own schedule.
+//
+// Use NewLeaderElectedGarbageCollector to run this safely across multiple
+// control-plane replicas.
+type GarbageCollector struct {
+	client *entgen.Client
+	policy RetentionPolicy
+	clock  Clock
+
+	onMetrics MetricsHook
+}
+
+// NewGarbageCollector creates a GarbageCollector enforcing policy against
+// client. It uses SystemClock until SetClock overrides it.
+func NewGarbageCollector(client *entgen.Client, policy RetentionPolicy) *GarbageCollector {
+	return &GarbageCollector{client: client, policy: policy, clock: SystemClock{}}
+}
+
+// SetClock overrides the Clock used to evaluate grace periods, for tests
+// that need to advance past a retention window without sleeping.
+func (gc *GarbageCollector) SetClock(clock Clock) {
+	gc.clock = clock
+}
+
+// SetMetricsHook registers a callback invoked with every Run's Metrics.
+func (gc *GarbageCollector) SetMetricsHook(hook MetricsHook) {
+	gc.onMetrics = hook
+}
+
+// Run executes one GC pass: scrubbing orphaned encrypted blobs on
+// soft-deleted Exchanges and Bots past their grace period. It always
+// reports Metrics, even when it returns an error, so a partial run's
+// progress isn't lost.
+func (gc *GarbageCollector) Run(ctx context.Context) (Metrics, error) {
+	start := gc.clock.Now()
+	var errs *multierror.Error
+	var deleted int
+
+	n, err := gc.scrubExchanges(ctx)
+	deleted += n
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	n, err = gc.scrubBots(ctx)
+	deleted += n
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	metrics := Metrics{
+		Deleted:  deleted,
+		Errors:   errorCount(errs),
+		Duration: gc.clock.Now().Sub(start),
+	}
+	if gc.onMetrics != nil {
+		gc.onMetrics(metrics)
+	}
+	return metrics, errs.ErrorOrNil()
+}
+
+// scrubExchanges clears config on every Exchange soft-deleted past
+// ExchangeGracePeriod, so its encrypted credentials can no longer be
+// decrypted even if the row is later restored.
+func (gc *GarbageCollector) scrubExchanges(ctx context.Context) (int, error) {
+	if gc.policy.ExchangeGracePeriod <= 0 {
+		return 0, nil
+	}
+	cutoff := gc.clock.Now().Add(-gc.policy.ExchangeGracePeriod)
+	ctx = mixin.IncludeDeleted(ctx)
+
+	rows, err := gc.client.Exchange.Query().
+		Where(entexchange.DeletedAtLTE(cutoff)).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("secrets: gc: list expired exchanges: %w", err)
+	}
+
+	var scrubbed int
+	var errs *multierror.Error
+	for _, row := range rows {
+		if row.Config == nil {
+			continue
+		}
+		if _, err := gc.client.Exchange.UpdateOneID(row.ID).ClearConfig().Save(ctx); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("secrets: gc: exchange %s: %w", row.ID, err))
+			continue
+		}
+		scrubbed++
+	}
+	return scrubbed, errs.ErrorOrNil()
+}
+
+// errorCount reports how many errors errs accumulates, treating a nil
+// *multierror.Error (the zero value produced when nothing failed) as zero.
+func errorCount(errs *multierror.Error) int {
+	if errs == nil {
+		return 0
+	}
+	return len(errs.Errors)
+}
+
+// scrubBots clears secure_config on every Bot soft-deleted past
+// BotGracePeriod, so its encrypted credentials can no longer be decrypted
+// even if the row is later restored.
+func (gc *GarbageCollector) scrubBots(ctx context.Context) (int, error) {
+	if gc.policy.BotGracePeriod <= 0 {
+		return 0, nil
+	}
+	cutoff := gc.clock.Now().Add(-gc.policy.BotGracePeriod)
+	ctx = mixin.IncludeDeleted(ctx)
+
+	rows, err := gc.client.Bot.Query().
+		Where(entbot.DeletedAtLTE(cutoff)).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("secrets: gc: list expired bots: %w", err)
+	}
+
+	var scrubbed int
+	var errs *multierror.Error
+	for _, row := range rows {
+		if row.SecureConfig == nil {
+			continue
+		}
+		if _, err := gc.client.Bot.UpdateOneID(row.ID).ClearSecureConfig().Save(ctx); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("secrets: gc: bot %s: %w", row.ID, err))
+			continue
+		}
+		scrubbed++
+	}
+	return scrubbed, errs.ErrorOrNil()
+}
@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"volaticloud/internal/usage"
+)
+
+// DefaultGCInterval is how often LeaderElectedGarbageCollector runs a GC
+// pass when no interval is configured.
+const DefaultGCInterval = time.Hour
+
+// LeaderElectedGarbageCollector runs a GarbageCollector on a fixed interval,
+// guarded by a distributed lease so only one control-plane replica GCs at a
+// time. It reuses usage.LeaseStore rather than introducing a second
+// leader-election mechanism alongside usage.LeaderElectedAggregator's.
+type LeaderElectedGarbageCollector struct {
+	gc        *GarbageCollector
+	store     usage.LeaseStore
+	leaseName string
+	holder    string
+	leaseTTL  time.Duration
+	interval  time.Duration
+
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewLeaderElectedGarbageCollector wraps gc with a lease named leaseName in
+// store, identifying itself as holder (typically a hostname or pod name).
+// interval of zero uses DefaultGCInterval. leaseTTL of zero defaults to
+// interval itself, so a replica holds exclusivity for one full GC cycle and,
+// if it dies, the lease has naturally expired by the time the next cycle's
+// tick tries to acquire it.
+func NewLeaderElectedGarbageCollector(gc *GarbageCollector, store usage.LeaseStore, leaseName, holder string, interval, leaseTTL time.Duration) *LeaderElectedGarbageCollector {
+	if interval <= 0 {
+		interval = DefaultGCInterval
+	}
+	if leaseTTL <= 0 {
+		leaseTTL = interval
+	}
+
+	return &LeaderElectedGarbageCollector{
+		gc:        gc,
+		store:     store,
+		leaseName: leaseName,
+		holder:    holder,
+		leaseTTL:  leaseTTL,
+		interval:  interval,
+		stopChan:  make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+}
+
+// Start begins the periodic GC loop in the background. Call Stop to release
+// the lease and stop the loop.
+func (l *LeaderElectedGarbageCollector) Start(ctx context.Context) {
+	go l.loop(ctx)
+}
+
+// Stop releases the lease (if held) and stops the loop, blocking until it
+// has exited.
+func (l *LeaderElectedGarbageCollector) Stop() {
+	close(l.stopChan)
+	<-l.doneChan
+}
+
+func (l *LeaderElectedGarbageCollector) loop(ctx context.Context) {
+	defer close(l.doneChan)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.release()
+			return
+		case <-l.stopChan:
+			l.release()
+			return
+		case <-ticker.C:
+			l.tick(ctx)
+		}
+	}
+}
+
+// tick tries to acquire or renew the lease and, if held, runs one GC pass.
+func (l *LeaderElectedGarbageCollector) tick(ctx context.Context) {
+	held, err := l.store.TryAcquire(ctx, l.leaseName, l.holder, l.leaseTTL)
+	if err != nil || !held {
+		return
+	}
+
+	if _, err := l.gc.Run(ctx); err != nil {
+		log.Printf("secrets: garbage collection pass failed: %v", err)
+	}
+}
+
+func (l *LeaderElectedGarbageCollector) release() {
+	_ = l.store.Release(context.Background(), l.leaseName, l.holder)
+}
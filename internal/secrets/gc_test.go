@@ -0,0 +1,114 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/mixin"
+)
+
+// fakeClock is a Clock that only advances when told to, so tests can cross
+// a retention grace period deterministically.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// TestGarbageCollector_ScrubsExchangeAfterGracePeriod soft-deletes an
+// Exchange, advances the Clock past its grace period, runs one GC tick, and
+// asserts the ciphertext is unreadable afterward (config cleared).
+func TestGarbageCollector_ScrubsExchangeAfterGracePeriod(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_gc_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Now()}
+
+	// Soft-delete the row directly (this test doesn't wire up
+	// db.SetupSoftDelete's hooks, same as migrate_test.go).
+	_, err = client.Exchange.UpdateOneID(row.ID).SetDeletedAt(clock.Now()).Save(ctx)
+	require.NoError(t, err)
+
+	policy := RetentionPolicy{ExchangeGracePeriod: 24 * time.Hour}
+	gc := NewGarbageCollector(client, policy)
+	gc.SetClock(clock)
+
+	var reported Metrics
+	gc.SetMetricsHook(func(m Metrics) { reported = m })
+
+	// Still within the grace period: nothing scrubbed yet.
+	metrics, err := gc.Run(ctx)
+	require.NoError(t, err)
+	require.Zero(t, metrics.Deleted)
+	require.Equal(t, metrics, reported)
+
+	after, err := client.Exchange.Get(mixin.IncludeDeleted(ctx), row.ID)
+	require.NoError(t, err)
+	require.NotNil(t, after.Config)
+
+	// Advance past the grace period and GC again.
+	clock.Advance(25 * time.Hour)
+	metrics, err = gc.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.Deleted)
+	require.Zero(t, metrics.Errors)
+
+	after, err = client.Exchange.Get(mixin.IncludeDeleted(ctx), row.ID)
+	require.NoError(t, err)
+	require.Nil(t, after.Config)
+}
+
+// TestGarbageCollector_ZeroGracePeriodDisablesScrubbing verifies a
+// RetentionPolicy with a zero grace period leaves an entity untouched,
+// regardless of how old its soft-delete is.
+func TestGarbageCollector_ZeroGracePeriodDisablesScrubbing(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_gc_disabled_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Now()}
+	_, err = client.Exchange.UpdateOneID(row.ID).SetDeletedAt(clock.Now()).Save(ctx)
+	require.NoError(t, err)
+	clock.Advance(365 * 24 * time.Hour)
+
+	gc := NewGarbageCollector(client, RetentionPolicy{})
+	gc.SetClock(clock)
+
+	metrics, err := gc.Run(ctx)
+	require.NoError(t, err)
+	require.Zero(t, metrics.Deleted)
+
+	after, err := client.Exchange.Get(mixin.IncludeDeleted(ctx), row.ID)
+	require.NoError(t, err)
+	require.NotNil(t, after.Config)
+}
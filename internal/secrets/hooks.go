@@ -6,6 +6,7 @@ import (
 
 	"entgo.io/ent"
 
+	"volaticloud/internal/audit"
 	"volaticloud/internal/bot"
 	entgen "volaticloud/internal/ent"
 	"volaticloud/internal/exchange"
@@ -41,6 +42,14 @@ func EncryptHook(fieldName string, paths []string) ent.Hook {
 				return nil, fmt.Errorf("secrets: set %s: %w", fieldName, err)
 			}
 
+			_ = audit.Emit(ctx, audit.Record{
+				Actor:      audit.ActorFromContext(ctx),
+				Action:     "secrets.encrypt_field",
+				TargetType: m.Type(),
+				TargetID:   fieldName,
+				RequestID:  audit.RequestIDFromContext(ctx),
+			})
+
 			return next.Mutate(ctx, m)
 		})
 	}
@@ -56,7 +65,12 @@ func RegisterDecryptInterceptors(client *entgen.Client) {
 				if err != nil || !Enabled() {
 					return result, err
 				}
-				return decryptExchangeResults(result)
+				result, err = decryptExchangeResults(result)
+				if err != nil {
+					return result, err
+				}
+				emitDecryptAudit(ctx, "Exchange", "config")
+				return result, nil
 			})
 		}),
 	)
@@ -68,7 +82,12 @@ func RegisterDecryptInterceptors(client *entgen.Client) {
 				if err != nil || !Enabled() {
 					return result, err
 				}
-				return decryptRunnerResults(result)
+				result, err = decryptRunnerResults(result)
+				if err != nil {
+					return result, err
+				}
+				emitDecryptAudit(ctx, "BotRunner", "config")
+				return result, nil
 			})
 		}),
 	)
@@ -80,12 +99,32 @@ func RegisterDecryptInterceptors(client *entgen.Client) {
 				if err != nil || !Enabled() {
 					return result, err
 				}
-				return decryptBotResults(result)
+				result, err = decryptBotResults(result)
+				if err != nil {
+					return result, err
+				}
+				emitDecryptAudit(ctx, "Bot", "secure_config")
+				return result, nil
 			})
 		}),
 	)
 }
 
+// emitDecryptAudit records that targetType's fieldName was read and
+// decrypted under this query, so secret reads show up in the audit trail
+// alongside the writes EncryptHook already records. It's emitted once per
+// query rather than once per row, matching EncryptHook's per-mutation (not
+// per-field-value) granularity.
+func emitDecryptAudit(ctx context.Context, targetType, fieldName string) {
+	_ = audit.Emit(ctx, audit.Record{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     "secrets.decrypt_field",
+		TargetType: targetType,
+		TargetID:   fieldName,
+		RequestID:  audit.RequestIDFromContext(ctx),
+	})
+}
+
 func decryptExchangeResults(result ent.Value) (ent.Value, error) {
 	switch v := result.(type) {
 	case []*entgen.Exchange:
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// KeyProvider wraps and unwraps data-encryption keys (DEKs) under a
+// key-encryption key (KEK) it manages. Implementations never need to expose
+// the KEK itself — callers only ever see wrapped DEK bytes.
+type KeyProvider interface {
+	// Wrap encrypts dek under the provider's current KEK.
+	Wrap(ctx context.Context, dek []byte) (wrapped []byte, err error)
+	// Unwrap decrypts a DEK previously produced by Wrap. Implementations
+	// must accept DEKs wrapped by any KEK version they still have access
+	// to, not just the current one, so rotation doesn't strand old data —
+	// construct the provider with its retired KEK(s) still attached when
+	// repointing DefaultEncryptor at a new one.
+	Unwrap(ctx context.Context, wrapped []byte) (dek []byte, err error)
+	// CurrentKEKID identifies the KEK that Wrap currently uses. It is stored
+	// alongside the wrapped DEK so Rewrap and audits know which key to use.
+	CurrentKEKID() string
+}
+
+// LocalKeyProvider is a dev/self-hosted KeyProvider that wraps DEKs with a
+// local AES-256-GCM key. It is the envelope-encryption analogue of the
+// legacy staticEncryptor; it is used only when a caller passes it to
+// InitEnvelope or NewEnvelopeEncryptor.
+type LocalKeyProvider struct {
+	kekID   string
+	kek     []byte
+	oldKeks [][]byte
+}
+
+// NewLocalKeyProvider builds a LocalKeyProvider from a 32-byte AES key and an
+// identifier for it (used as the stored kekID, e.g. "local-v1"). oldKeks are
+// retired KEKs this provider should still accept in Unwrap — pass the
+// previous KEK here when rotating so rows wrapped under it keep decrypting,
+// mirroring staticEncryptor's primaryKey/oldKeys pattern.
+func NewLocalKeyProvider(kekID string, kek []byte, oldKeks ...[]byte) (*LocalKeyProvider, error) {
+	if len(kek) != 32 {
+		return nil, fmt.Errorf("secrets: KEK must be 32 bytes (AES-256), got %d", len(kek))
+	}
+	for i, old := range oldKeks {
+		if len(old) != 32 {
+			return nil, fmt.Errorf("secrets: old KEK [%d] must be 32 bytes (AES-256), got %d", i, len(old))
+		}
+	}
+	return &LocalKeyProvider{kekID: kekID, kek: kek, oldKeks: oldKeks}, nil
+}
+
+func (p *LocalKeyProvider) Wrap(_ context.Context, dek []byte) ([]byte, error) {
+	gcm, err := newGCM(p.kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("secrets: nonce generation error: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, dek, nil), nil
+}
+
+// Unwrap tries the current KEK first, then each retired KEK in order, so DEKs
+// wrapped before the most recent rotation still decrypt.
+func (p *LocalKeyProvider) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	var lastErr error
+	for _, kek := range append([][]byte{p.kek}, p.oldKeks...) {
+		gcm, err := newGCM(kek)
+		if err != nil {
+			return nil, err
+		}
+		nonceSize := gcm.NonceSize()
+		if len(wrapped) < nonceSize {
+			lastErr = fmt.Errorf("secrets: wrapped DEK too short")
+			continue
+		}
+		nonce, ct := wrapped[:nonceSize], wrapped[nonceSize:]
+		dek, err := gcm.Open(nil, nonce, ct, nil)
+		if err == nil {
+			return dek, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("secrets: unwrap failed with current and %d retired KEK(s): %w", len(p.oldKeks), lastErr)
+}
+
+func (p *LocalKeyProvider) CurrentKEKID() string {
+	return p.kekID
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: cipher error: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,159 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	gcpkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// AWSKMSProvider wraps DEKs with an AWS KMS customer master key. The
+// plaintext DEK never touches disk; only kms:Encrypt/kms:Decrypt calls cross
+// the network.
+type AWSKMSProvider struct {
+	client *kms.Client
+	keyID  string
+}
+
+// NewAWSKMSProvider builds an AWSKMSProvider for the given KMS key ID or
+// alias (e.g. "alias/volaticloud-secrets").
+func NewAWSKMSProvider(client *kms.Client, keyID string) *AWSKMSProvider {
+	return &AWSKMSProvider{client: client, keyID: keyID}
+}
+
+func (p *AWSKMSProvider) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	out, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("secrets: aws kms encrypt: %w", err)
+	}
+	return out.CiphertextBlob, nil
+}
+
+// Unwrap decrypts wrapped without constraining which CMK performs it. AWS
+// KMS resolves the originating key from metadata embedded in the
+// ciphertext blob itself, so a DEK wrapped under a since-rotated-away CMK
+// still decrypts as long as this caller's IAM principal retains
+// kms:Decrypt on that CMK.
+func (p *AWSKMSProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("secrets: aws kms decrypt: %w", err)
+	}
+	return out.Plaintext, nil
+}
+
+func (p *AWSKMSProvider) CurrentKEKID() string {
+	return p.keyID
+}
+
+// GCPKMSProvider wraps DEKs with a Google Cloud KMS CryptoKey.
+type GCPKMSProvider struct {
+	client          *gcpkms.KeyManagementClient
+	keyResource     string // e.g. "projects/p/locations/l/keyRings/r/cryptoKeys/k"
+	oldKeyResources []string
+}
+
+// NewGCPKMSProvider builds a GCPKMSProvider for the given fully-qualified
+// CryptoKey resource name. oldKeyResources are retired CryptoKey resources
+// this provider should still accept in Unwrap — pass the previous
+// CryptoKey here when rotating to an entirely new one, since GCP KMS (unlike
+// AWS) requires Decrypt to name the exact CryptoKey resource and won't
+// resolve it from ciphertext alone.
+func NewGCPKMSProvider(client *gcpkms.KeyManagementClient, keyResource string, oldKeyResources ...string) *GCPKMSProvider {
+	return &GCPKMSProvider{client: client, keyResource: keyResource, oldKeyResources: oldKeyResources}
+}
+
+func (p *GCPKMSProvider) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	resp, err := p.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      p.keyResource,
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("secrets: gcp kms encrypt: %w", err)
+	}
+	return resp.Ciphertext, nil
+}
+
+// Unwrap tries the current CryptoKey resource first, then each retired one
+// in order, so DEKs wrapped under a CryptoKey this provider has since
+// rotated away from still decrypt.
+func (p *GCPKMSProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var lastErr error
+	for _, resource := range append([]string{p.keyResource}, p.oldKeyResources...) {
+		resp, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{
+			Name:       resource,
+			Ciphertext: wrapped,
+		})
+		if err == nil {
+			return resp.Plaintext, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("secrets: gcp kms decrypt: failed with current and %d retired CryptoKey(s): %w", len(p.oldKeyResources), lastErr)
+}
+
+func (p *GCPKMSProvider) CurrentKEKID() string {
+	return p.keyResource
+}
+
+// VaultTransitProvider wraps DEKs using HashiCorp Vault's Transit secrets
+// engine. Wrapped DEKs are Vault's own ciphertext strings ("vault:v1:...")
+// stored as raw bytes so they round-trip through the envelope unchanged.
+type VaultTransitProvider struct {
+	client  *vaultapi.Client
+	mount   string // transit mount path, e.g. "transit"
+	keyName string
+}
+
+// NewVaultTransitProvider builds a VaultTransitProvider for the named
+// Transit key under mount (defaults to "transit" if empty).
+func NewVaultTransitProvider(client *vaultapi.Client, mount, keyName string) *VaultTransitProvider {
+	if mount == "" {
+		mount = "transit"
+	}
+	return &VaultTransitProvider{client: client, mount: mount, keyName: keyName}
+}
+
+func (p *VaultTransitProvider) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		fmt.Sprintf("%s/encrypt/%s", p.mount, p.keyName),
+		map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(dek)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: vault transit encrypt: %w", err)
+	}
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("secrets: vault transit encrypt: missing ciphertext in response")
+	}
+	return []byte(ciphertext), nil
+}
+
+func (p *VaultTransitProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		fmt.Sprintf("%s/decrypt/%s", p.mount, p.keyName),
+		map[string]interface{}{"ciphertext": string(wrapped)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: vault transit decrypt: %w", err)
+	}
+	plaintextB64, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("secrets: vault transit decrypt: missing plaintext in response")
+	}
+	return base64.StdEncoding.DecodeString(plaintextB64)
+}
+
+func (p *VaultTransitProvider) CurrentKEKID() string {
+	return fmt.Sprintf("%s/%s", p.mount, p.keyName)
+}
@@ -0,0 +1,367 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+
+	"volaticloud/internal/bot"
+	entgen "volaticloud/internal/ent"
+	entbot "volaticloud/internal/ent/bot"
+	entbotrunner "volaticloud/internal/ent/botrunner"
+	entexchange "volaticloud/internal/ent/exchange"
+	"volaticloud/internal/exchange"
+	"volaticloud/internal/runner"
+)
+
+// EntityType selects which persisted entity MigrateEntity walks.
+type EntityType string
+
+const (
+	EntityExchange  EntityType = "exchange"
+	EntityBotRunner EntityType = "runner"
+	EntityBot       EntityType = "bot"
+)
+
+const (
+	migrateBatchSize   = 200
+	migrateMaxAttempts = 5
+	migrateBaseBackoff = 20 * time.Millisecond
+)
+
+// MigrationProgress reports how a MigrateEntity run touched persisted rows.
+type MigrationProgress struct {
+	Scanned   int
+	Rewrapped int
+	Skipped   int
+	Conflicts int
+	Errors    int
+}
+
+// MigrateEntity walks every row of entityType in id order and re-encrypts its
+// secret fields under DefaultEncryptor's current key, a few hundred rows at a
+// time. Each row is read, rewrapped, and written back guarded by an
+// optimistic-concurrency check on updated_at (UPDATE ... WHERE id = ? AND
+// updated_at = ?): if a concurrent writer touched the row first, the update
+// affects zero rows, so the row is refetched and retried with a jittered
+// backoff, up to migrateMaxAttempts before it's counted as a conflict and
+// left for the next run. Rows whose fields are already tagged with the
+// current KEK are skipped without a read-modify-write round trip. If dryRun
+// is true, no writes are performed; Rewrapped/Skipped/Conflicts describe what
+// a real run would do.
+//
+// As with RewrapAll, DefaultEncryptor's KeyProvider must still retain the
+// old KEK as a fallback while this runs — every row not yet visited is
+// still wrapped under it, and RewrapFields needs to unwrap before it can
+// rewrap under the new key.
+func MigrateEntity(ctx context.Context, client *entgen.Client, entityType EntityType, dryRun bool) (MigrationProgress, error) {
+	switch entityType {
+	case EntityExchange:
+		return migrateExchanges(ctx, client, dryRun)
+	case EntityBotRunner:
+		return migrateBotRunners(ctx, client, dryRun)
+	case EntityBot:
+		return migrateBots(ctx, client, dryRun)
+	default:
+		return MigrationProgress{}, fmt.Errorf("secrets: migrate: unknown entity type %q", entityType)
+	}
+}
+
+func migrateExchanges(ctx context.Context, client *entgen.Client, dryRun bool) (MigrationProgress, error) {
+	var progress MigrationProgress
+	var errs *multierror.Error
+	cursor := uuid.Nil
+
+	for {
+		rows, err := client.Exchange.Query().
+			Where(entexchange.IDGT(cursor)).
+			Order(ent.Asc(entexchange.FieldID)).
+			Limit(migrateBatchSize).
+			All(ctx)
+		if err != nil {
+			return progress, fmt.Errorf("secrets: migrate exchange: list: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		cursor = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			progress.Scanned++
+			if row.Config == nil {
+				continue
+			}
+			if fieldsCurrent(row.Config, exchange.SecretConfigPaths) {
+				progress.Skipped++
+				continue
+			}
+			if dryRun {
+				progress.Rewrapped++
+				continue
+			}
+
+			current := row
+			conflicted, err := retryOnConflict(func() (bool, error) {
+				if err := RewrapFields(current.Config, exchange.SecretConfigPaths); err != nil {
+					return false, fmt.Errorf("exchange %s: %w", current.ID, err)
+				}
+				n, err := client.Exchange.Update().
+					Where(entexchange.ID(current.ID), entexchange.UpdatedAtEQ(current.UpdatedAt)).
+					SetConfig(current.Config).
+					Save(ctx)
+				if err != nil {
+					return false, fmt.Errorf("exchange %s: save: %w", current.ID, err)
+				}
+				if n == 0 {
+					refetched, err := client.Exchange.Get(ctx, current.ID)
+					if err != nil {
+						return false, fmt.Errorf("exchange %s: refetch after conflict: %w", current.ID, err)
+					}
+					current = refetched
+					return true, nil
+				}
+				return false, nil
+			})
+			switch {
+			case err != nil:
+				errs = multierror.Append(errs, err)
+				progress.Errors++
+			case conflicted:
+				progress.Conflicts++
+			default:
+				progress.Rewrapped++
+			}
+		}
+	}
+
+	return progress, errs.ErrorOrNil()
+}
+
+func migrateBotRunners(ctx context.Context, client *entgen.Client, dryRun bool) (MigrationProgress, error) {
+	var progress MigrationProgress
+	var errs *multierror.Error
+	cursor := uuid.Nil
+
+	for {
+		rows, err := client.BotRunner.Query().
+			Where(entbotrunner.IDGT(cursor)).
+			Order(ent.Asc(entbotrunner.FieldID)).
+			Limit(migrateBatchSize).
+			All(ctx)
+		if err != nil {
+			return progress, fmt.Errorf("secrets: migrate runner: list: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		cursor = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			progress.Scanned++
+			configCurrent := row.Config == nil || fieldsCurrent(row.Config, runner.SecretConfigPaths)
+			s3ConfigCurrent := row.S3Config == nil || fieldsCurrent(row.S3Config, runner.SecretS3ConfigPaths)
+			if configCurrent && s3ConfigCurrent {
+				progress.Skipped++
+				continue
+			}
+			if dryRun {
+				progress.Rewrapped++
+				continue
+			}
+
+			current := row
+			conflicted, err := retryOnConflict(func() (bool, error) {
+				update := client.BotRunner.Update().
+					Where(entbotrunner.ID(current.ID), entbotrunner.UpdatedAtEQ(current.UpdatedAt))
+
+				if current.Config != nil {
+					if err := RewrapFields(current.Config, runner.SecretConfigPaths); err != nil {
+						return false, fmt.Errorf("runner %s config: %w", current.ID, err)
+					}
+					update = update.SetConfig(current.Config)
+				}
+				if current.S3Config != nil {
+					if err := RewrapFields(current.S3Config, runner.SecretS3ConfigPaths); err != nil {
+						return false, fmt.Errorf("runner %s s3_config: %w", current.ID, err)
+					}
+					update = update.SetS3Config(current.S3Config)
+				}
+
+				n, err := update.Save(ctx)
+				if err != nil {
+					return false, fmt.Errorf("runner %s: save: %w", current.ID, err)
+				}
+				if n == 0 {
+					refetched, err := client.BotRunner.Get(ctx, current.ID)
+					if err != nil {
+						return false, fmt.Errorf("runner %s: refetch after conflict: %w", current.ID, err)
+					}
+					current = refetched
+					return true, nil
+				}
+				return false, nil
+			})
+			switch {
+			case err != nil:
+				errs = multierror.Append(errs, err)
+				progress.Errors++
+			case conflicted:
+				progress.Conflicts++
+			default:
+				progress.Rewrapped++
+			}
+		}
+	}
+
+	return progress, errs.ErrorOrNil()
+}
+
+func migrateBots(ctx context.Context, client *entgen.Client, dryRun bool) (MigrationProgress, error) {
+	var progress MigrationProgress
+	var errs *multierror.Error
+	cursor := uuid.Nil
+
+	for {
+		rows, err := client.Bot.Query().
+			Where(entbot.IDGT(cursor)).
+			Order(ent.Asc(entbot.FieldID)).
+			Limit(migrateBatchSize).
+			All(ctx)
+		if err != nil {
+			return progress, fmt.Errorf("secrets: migrate bot: list: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		cursor = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			progress.Scanned++
+			if row.SecureConfig == nil {
+				continue
+			}
+			if fieldsCurrent(row.SecureConfig, bot.SecretConfigPaths) {
+				progress.Skipped++
+				continue
+			}
+			if dryRun {
+				progress.Rewrapped++
+				continue
+			}
+
+			current := row
+			conflicted, err := retryOnConflict(func() (bool, error) {
+				if err := RewrapFields(current.SecureConfig, bot.SecretConfigPaths); err != nil {
+					return false, fmt.Errorf("bot %s: %w", current.ID, err)
+				}
+				n, err := client.Bot.Update().
+					Where(entbot.ID(current.ID), entbot.UpdatedAtEQ(current.UpdatedAt)).
+					SetSecureConfig(current.SecureConfig).
+					Save(ctx)
+				if err != nil {
+					return false, fmt.Errorf("bot %s: save: %w", current.ID, err)
+				}
+				if n == 0 {
+					refetched, err := client.Bot.Get(ctx, current.ID)
+					if err != nil {
+						return false, fmt.Errorf("bot %s: refetch after conflict: %w", current.ID, err)
+					}
+					current = refetched
+					return true, nil
+				}
+				return false, nil
+			})
+			switch {
+			case err != nil:
+				errs = multierror.Append(errs, err)
+				progress.Errors++
+			case conflicted:
+				progress.Conflicts++
+			default:
+				progress.Rewrapped++
+			}
+		}
+	}
+
+	return progress, errs.ErrorOrNil()
+}
+
+// retryOnConflict runs attempt up to migrateMaxAttempts times, sleeping a
+// jittered, linearly increasing backoff between tries. attempt returns
+// conflict=true when its optimistic-concurrency write matched zero rows,
+// after refreshing whatever state it closes over so the next try reads the
+// latest row. retryOnConflict reports conflicted=true if every attempt hit a
+// conflict, so the caller can count the row as a conflict rather than retry
+// forever.
+func retryOnConflict(attempt func() (conflict bool, err error)) (conflicted bool, err error) {
+	for try := 0; try < migrateMaxAttempts; try++ {
+		conflict, aerr := attempt()
+		if aerr != nil {
+			return false, aerr
+		}
+		if !conflict {
+			return false, nil
+		}
+		backoff := migrateBaseBackoff*time.Duration(try+1) + time.Duration(rand.Int63n(int64(migrateBaseBackoff)))
+		time.Sleep(backoff)
+	}
+	return true, nil
+}
+
+// fieldsCurrent reports whether every encrypted field at paths is already an
+// envelope wrapped under DefaultEncryptor's current KEK, so migration can
+// skip the row entirely. It returns false (never skip) whenever
+// DefaultEncryptor isn't envelope-based, since staticEncryptor has no KEK
+// identity to compare against.
+func fieldsCurrent(config map[string]interface{}, paths []string) bool {
+	ider, ok := DefaultEncryptor.(kekIdentifier)
+	if !ok {
+		return false
+	}
+	currentKEKID := ider.CurrentKEKID()
+
+	for _, path := range paths {
+		value, ok := lookupField(config, path)
+		if !ok || !IsEncrypted(value) {
+			continue
+		}
+		if !isEnvelopeFormat(value) {
+			return false
+		}
+		env, err := decodeEnvelope(value)
+		if err != nil || env.KEKID != currentKEKID {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupField reads (without modifying) the string value at a dot-separated
+// path into config, mirroring the traversal transformField performs.
+func lookupField(config map[string]interface{}, path string) (string, bool) {
+	parts := strings.Split(path, ".")
+	current := config
+	for i := 0; i < len(parts)-1; i++ {
+		next, ok := current[parts[i]]
+		if !ok {
+			return "", false
+		}
+		nextMap, ok := next.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		current = nextMap
+	}
+	value, ok := current[parts[len(parts)-1]]
+	if !ok {
+		return "", false
+	}
+	str, ok := value.(string)
+	return str, ok
+}
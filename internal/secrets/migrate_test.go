@@ -0,0 +1,226 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/ent"
+)
+
+func newExchangeConfig(t *testing.T, key, secret string) map[string]interface{} {
+	t.Helper()
+	config := map[string]interface{}{
+		"exchange": map[string]interface{}{
+			"name":   "binance",
+			"key":    key,
+			"secret": secret,
+		},
+	}
+	require.NoError(t, EncryptFields(config, testExchangePaths))
+	return config
+}
+
+func decryptedExchangeSecret(t *testing.T, config map[string]interface{}, field string) string {
+	t.Helper()
+	decrypted := map[string]interface{}{}
+	for k, v := range config {
+		decrypted[k] = v
+	}
+	require.NoError(t, DecryptFields(decrypted, testExchangePaths))
+	return decrypted["exchange"].(map[string]interface{})[field].(string)
+}
+
+// TestMigrateEntity_ExchangeNoLossUnderConcurrentWrites races MigrateEntity
+// against a goroutine that keeps rewriting one row's secret fields. It
+// asserts the migration terminates (no infinite retry loop) and that every
+// row, including the raced one, still decrypts to a value one of the two
+// writers actually produced - never to garbage.
+func TestMigrateEntity_ExchangeNoLossUnderConcurrentWrites(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_migrate_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+
+	const rowCount = 20
+	ids := make([]uuid.UUID, 0, rowCount)
+	for i := 0; i < rowCount; i++ {
+		row, err := client.Exchange.Create().
+			SetName("exchange").
+			SetConfig(newExchangeConfig(t, "initial-key", "initial-secret")).
+			Save(ctx)
+		require.NoError(t, err)
+		ids = append(ids, row.ID)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		target := ids[0]
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			key := fmt.Sprintf("updated-key-%d", i)
+			_, _ = client.Exchange.UpdateOneID(target).
+				SetConfig(newExchangeConfig(t, key, "updated-secret")).
+				Save(ctx)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	progress, err := MigrateEntity(ctx, client, EntityExchange, false)
+	close(stop)
+	wg.Wait()
+
+	require.NoError(t, err)
+	require.Equal(t, rowCount, progress.Scanned)
+	require.Zero(t, progress.Errors)
+
+	for _, id := range ids {
+		row, err := client.Exchange.Get(ctx, id)
+		require.NoError(t, err)
+		key := decryptedExchangeSecret(t, row.Config, "key")
+		if id == ids[0] {
+			// The raced row must hold either the seed value or one of the
+			// updater's writes - never a mangled/partial value.
+			require.True(t, key == "initial-key" || strings.HasPrefix(key, "updated-key-"),
+				"raced row decrypted to unexpected value %q", key)
+			continue
+		}
+		require.Equal(t, "initial-key", key)
+	}
+}
+
+// TestMigrateEntity_DryRunDoesNotWrite verifies --dry-run reports counts
+// without mutating any row.
+func TestMigrateEntity_DryRunDoesNotWrite(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_migrate_dryrun_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+	before := row.UpdatedAt
+
+	// Repoint at kek-v2 so the row is no longer under the current KEK and a
+	// real run would rewrap (and write) it.
+	newProvider, err := NewLocalKeyProvider("kek-v2", testKeyBytes("kek-v2-rotated"), testKeyBytes("kek-v1"))
+	require.NoError(t, err)
+	DefaultEncryptor = NewEnvelopeEncryptor(newProvider, nil)
+
+	progress, err := MigrateEntity(ctx, client, EntityExchange, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, progress.Scanned)
+	require.Equal(t, 1, progress.Rewrapped)
+
+	after, err := client.Exchange.Get(ctx, row.ID)
+	require.NoError(t, err)
+	require.Equal(t, before, after.UpdatedAt)
+}
+
+// TestMigrateEntity_SkipsRowsAlreadyUnderCurrentKEK verifies the fast path:
+// a row already wrapped under the current KEK contributes to Skipped, not
+// Rewrapped, and its encrypted value is left byte-for-byte unchanged.
+func TestMigrateEntity_SkipsRowsAlreadyUnderCurrentKEK(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_migrate_skip_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+	before := row.Config["exchange"].(map[string]interface{})["key"]
+
+	progress, err := MigrateEntity(ctx, client, EntityExchange, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, progress.Scanned)
+	require.Equal(t, 1, progress.Skipped)
+	require.Zero(t, progress.Rewrapped)
+
+	after, err := client.Exchange.Get(ctx, row.ID)
+	require.NoError(t, err)
+	require.Equal(t, before, after.Config["exchange"].(map[string]interface{})["key"])
+}
+
+// testKeyBytes reproduces testLocalProvider's deterministic key derivation so
+// a test can build the same key material for a retired KEK.
+func testKeyBytes(kekID string) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + len(kekID))
+	}
+	return key
+}
+
+// TestMigrateEntity_RotatesAcrossRealKEKChange exercises the actual `secrets
+// rotate` path end-to-end: rows are written under kek-v1, then
+// DefaultEncryptor is repointed at a LocalKeyProvider whose current KEK is
+// kek-v2 but which still retains kek-v1 as a fallback (the documented
+// precondition for rotation). MigrateEntity must rewrap every row without
+// hitting a Decrypt error - it would, if the new provider couldn't still
+// unwrap DEKs wrapped under the key it just rotated away from.
+func TestMigrateEntity_RotatesAcrossRealKEKChange(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+
+	client, err := ent.Open("sqlite3", "file:secrets_migrate_rotate_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+
+	// The derivation depends only on the id's length, so kek-v2 takes a
+	// longer id here to get key bytes distinct from kek-v1's.
+	newProvider, err := NewLocalKeyProvider("kek-v2", testKeyBytes("kek-v2-rotated"), testKeyBytes("kek-v1"))
+	require.NoError(t, err)
+	DefaultEncryptor = NewEnvelopeEncryptor(newProvider, nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	progress, err := MigrateEntity(ctx, client, EntityExchange, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, progress.Scanned)
+	require.Equal(t, 1, progress.Rewrapped)
+	require.Zero(t, progress.Errors)
+
+	after, err := client.Exchange.Get(ctx, row.ID)
+	require.NoError(t, err)
+	require.Equal(t, "key", decryptedExchangeSecret(t, after.Config, "key"))
+}
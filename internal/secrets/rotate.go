@@ -0,0 +1,135 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"volaticloud/internal/bot"
+	entgen "volaticloud/internal/ent"
+	"volaticloud/internal/exchange"
+	"volaticloud/internal/runner"
+)
+
+// RotateSummary reports how many persisted secret blobs RewrapAll touched.
+type RotateSummary struct {
+	ExchangeConfigs  int
+	RunnerConfigs    int
+	RunnerS3Configs  int
+	BotSecureConfigs int
+	Errors           int
+}
+
+// kekIdentifier is implemented by Encryptors that wrap DEKs under an
+// identifiable KEK (currently only envelopeEncryptor). staticEncryptor does
+// not implement it, since its single long-lived key has no separate id.
+type kekIdentifier interface {
+	CurrentKEKID() string
+}
+
+// RewrapAll walks every persisted config known to carry encrypted fields and
+// rewraps them under newKeyID via DefaultEncryptor.Rewrap. Because Rewrap
+// never needs plaintext outside the KMS, this can run without elevated
+// access to the encryption key itself. newKeyID must match the KEK
+// DefaultEncryptor is currently configured to wrap under — point the
+// process at the new key first (InitEnvelope with a provider whose
+// CurrentKEKID is newKeyID and whose retired keys still include the old
+// one, so rows not yet rewrapped keep decrypting), then call RewrapAll to
+// migrate existing rows. Rows that fail to rewrap are recorded but do not
+// abort the walk.
+func RewrapAll(ctx context.Context, client *entgen.Client, newKeyID string) (RotateSummary, error) {
+	var summary RotateSummary
+
+	if newKeyID != "" {
+		ider, ok := DefaultEncryptor.(kekIdentifier)
+		if !ok {
+			return summary, fmt.Errorf("secrets: rewrap: default encryptor does not use a KEK (not configured via InitEnvelope)")
+		}
+		if current := ider.CurrentKEKID(); current != newKeyID {
+			return summary, fmt.Errorf("secrets: rewrap: default encryptor is wrapping under KEK %q, not %q; reconfigure it before rewrapping", current, newKeyID)
+		}
+	}
+
+	var errs *multierror.Error
+
+	exchanges, err := client.Exchange.Query().All(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("secrets: rotate: list exchanges: %w", err)
+	}
+	for _, e := range exchanges {
+		if e.Config == nil {
+			continue
+		}
+		if err := RewrapFields(e.Config, exchange.SecretConfigPaths); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("exchange %s: %w", e.ID, err))
+			summary.Errors++
+			continue
+		}
+		if _, err := e.Update().SetConfig(e.Config).Save(ctx); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("exchange %s: save: %w", e.ID, err))
+			summary.Errors++
+			continue
+		}
+		summary.ExchangeConfigs++
+	}
+
+	runners, err := client.BotRunner.Query().All(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("secrets: rotate: list runners: %w", err)
+	}
+	for _, r := range runners {
+		update := r.Update()
+		dirty := false
+
+		if r.Config != nil {
+			if err := RewrapFields(r.Config, runner.SecretConfigPaths); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("runner %s config: %w", r.ID, err))
+				summary.Errors++
+			} else {
+				update = update.SetConfig(r.Config)
+				dirty = true
+				summary.RunnerConfigs++
+			}
+		}
+		if r.S3Config != nil {
+			if err := RewrapFields(r.S3Config, runner.SecretS3ConfigPaths); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("runner %s s3_config: %w", r.ID, err))
+				summary.Errors++
+			} else {
+				update = update.SetS3Config(r.S3Config)
+				dirty = true
+				summary.RunnerS3Configs++
+			}
+		}
+		if dirty {
+			if _, err := update.Save(ctx); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("runner %s: save: %w", r.ID, err))
+				summary.Errors++
+			}
+		}
+	}
+
+	bots, err := client.Bot.Query().All(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("secrets: rotate: list bots: %w", err)
+	}
+	for _, b := range bots {
+		if b.SecureConfig == nil {
+			continue
+		}
+		if err := RewrapFields(b.SecureConfig, bot.SecretConfigPaths); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("bot %s: %w", b.ID, err))
+			summary.Errors++
+			continue
+		}
+		if _, err := b.Update().SetSecureConfig(b.SecureConfig).Save(ctx); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("bot %s: save: %w", b.ID, err))
+			summary.Errors++
+			continue
+		}
+		summary.BotSecureConfigs++
+	}
+
+	return summary, errs.ErrorOrNil()
+}
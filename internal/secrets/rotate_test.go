@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"volaticloud/internal/ent"
+)
+
+// TestRewrapAllRejectsMismatchedKEK verifies RewrapAll refuses to run when
+// the caller's newKeyID doesn't match the KEK DefaultEncryptor is currently
+// configured to wrap under, rather than silently rewrapping everything
+// under the wrong key.
+func TestRewrapAllRejectsMismatchedKEK(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_rewrap_mismatch_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	summary, err := RewrapAll(context.Background(), client, "kek-v2")
+	assert.Error(t, err)
+	assert.Zero(t, summary.ExchangeConfigs)
+}
+
+// TestRewrapAllRejectsNonKEKEncryptor verifies RewrapAll refuses to run
+// against staticEncryptor, which has no KEK identity to validate newKeyID
+// against.
+func TestRewrapAllRejectsNonKEKEncryptor(t *testing.T) {
+	require.NoError(t, Init(testKey()))
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_rewrap_static_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	_, err = RewrapAll(context.Background(), client, "kek-v2")
+	assert.Error(t, err)
+}
+
+// TestRewrapAllRewrapsUnderMatchingKEK verifies the happy path: once
+// DefaultEncryptor is already configured to wrap under newKeyID, RewrapAll
+// walks persisted configs and rewraps them under it.
+func TestRewrapAllRewrapsUnderMatchingKEK(t *testing.T) {
+	DefaultEncryptor = NewEnvelopeEncryptor(testLocalProvider(t, "kek-v1"), nil)
+	t.Cleanup(func() { DefaultEncryptor = nil })
+
+	client, err := ent.Open("sqlite3", "file:secrets_rewrap_match_test?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	ctx := context.Background()
+	row, err := client.Exchange.Create().
+		SetName("exchange").
+		SetConfig(newExchangeConfig(t, "key", "secret")).
+		Save(ctx)
+	require.NoError(t, err)
+
+	summary, err := RewrapAll(ctx, client, "kek-v1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.ExchangeConfigs)
+	assert.Zero(t, summary.Errors)
+
+	after, err := client.Exchange.Get(ctx, row.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "key", decryptedExchangeSecret(t, after.Config, "key"))
+}
@@ -0,0 +1,192 @@
+package codegen
+
+import "testing"
+
+func TestGenerateOperand_AtrScaled(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "above close by 1.5x ATR",
+			json: `{
+				"type": "ATR_SCALED",
+				"reference": {"type": "PRICE", "field": "close"},
+				"multiplier": 1.5,
+				"window": 14,
+				"direction": "above"
+			}`,
+			expected: "dataframe['close'] + 1.5 * ta.ATR(dataframe, timeperiod=14)",
+		},
+		{
+			name: "below an indicator by 2x ATR",
+			json: `{
+				"type": "ATR_SCALED",
+				"reference": {"type": "INDICATOR", "indicatorId": "ema_20"},
+				"multiplier": 2,
+				"window": 14,
+				"direction": "below"
+			}`,
+			expected: "dataframe['ema_20'] - 2 * ta.ATR(dataframe, timeperiod=14)",
+		},
+		{
+			name: "unknown direction",
+			json: `{
+				"type": "ATR_SCALED",
+				"reference": {"type": "PRICE", "field": "close"},
+				"multiplier": 1,
+				"window": 14,
+				"direction": "sideways"
+			}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator()
+			op := mustParseOperand(t, tt.json)
+
+			result, err := g.GenerateOperand(op)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GenerateOperand() expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("GenerateOperand() error = %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("GenerateOperand() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateOperand_AtrScaled_RepeatedWindowIsSelfContained(t *testing.T) {
+	g := NewGenerator()
+
+	first := mustParseOperand(t, `{
+		"type": "ATR_SCALED",
+		"reference": {"type": "PRICE", "field": "close"},
+		"multiplier": 1.5,
+		"window": 14,
+		"direction": "above"
+	}`)
+	second := mustParseOperand(t, `{
+		"type": "ATR_SCALED",
+		"reference": {"type": "PRICE", "field": "open"},
+		"multiplier": 2,
+		"window": 14,
+		"direction": "below"
+	}`)
+
+	firstResult, err := g.GenerateOperand(first)
+	if err != nil {
+		t.Fatalf("GenerateOperand() error = %v", err)
+	}
+	if firstResult != "dataframe['close'] + 1.5 * ta.ATR(dataframe, timeperiod=14)" {
+		t.Errorf("first GenerateOperand() = %q", firstResult)
+	}
+
+	// A second reference to the same window must compute ta.ATR inline again
+	// rather than read a dataframe['atr_14'] column nothing ever assigns -
+	// that would be a KeyError at backtest/live time.
+	secondResult, err := g.GenerateOperand(second)
+	if err != nil {
+		t.Fatalf("GenerateOperand() error = %v", err)
+	}
+	if secondResult != "dataframe['open'] - 2 * ta.ATR(dataframe, timeperiod=14)" {
+		t.Errorf("second GenerateOperand() = %q, want inline ta.ATR call", secondResult)
+	}
+
+	imports := g.GetRequiredImports()
+	if len(imports) != 1 || imports[0] != "talib" {
+		t.Errorf("GetRequiredImports() = %v, want [talib]", imports)
+	}
+}
+
+func TestGenerateOperand_VolatilityBand(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "bollinger upper band",
+			json: `{
+				"type": "VOLATILITY_BAND",
+				"kind": "bollinger",
+				"window": 20,
+				"stdDev": 2,
+				"band": "upper"
+			}`,
+			expected: "qtpylib.bollinger_bands(qtpylib.typical_price(dataframe), window=20, stds=2)['upper']",
+		},
+		{
+			name: "keltner lower band",
+			json: `{
+				"type": "VOLATILITY_BAND",
+				"kind": "keltner",
+				"window": 20,
+				"stdDev": 1.5,
+				"band": "lower"
+			}`,
+			expected: "qtpylib.keltner_channel(dataframe, window=20, atrs=1.5)['lower']",
+		},
+		{
+			name: "unknown kind",
+			json: `{
+				"type": "VOLATILITY_BAND",
+				"kind": "donchian",
+				"window": 20,
+				"stdDev": 2,
+				"band": "upper"
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "unknown band",
+			json: `{
+				"type": "VOLATILITY_BAND",
+				"kind": "bollinger",
+				"window": 20,
+				"stdDev": 2,
+				"band": "middle"
+			}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator()
+			op := mustParseOperand(t, tt.json)
+
+			result, err := g.GenerateOperand(op)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GenerateOperand() expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("GenerateOperand() error = %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("GenerateOperand() = %q, want %q", result, tt.expected)
+			}
+
+			imports := g.GetRequiredImports()
+			if len(imports) != 1 || imports[0] != "qtpylib" {
+				t.Errorf("GetRequiredImports() = %v, want [qtpylib]", imports)
+			}
+		})
+	}
+}
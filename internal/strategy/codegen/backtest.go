@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ExchangeFeeOverride overrides the default maker/taker fee for a single
+// exchange, for backtests that need to model fees more precisely than
+// Freqtrade's single "fee" setting allows.
+type ExchangeFeeOverride struct {
+	Maker float64 `json:"maker"`
+	Taker float64 `json:"taker"`
+}
+
+// BacktestSpec describes the parameters needed to render a Freqtrade
+// backtesting config.json for a generated strategy.
+type BacktestSpec struct {
+	StrategyName  string   `json:"strategy_name"`
+	ExchangeName  string   `json:"exchange_name"`
+	StakeCurrency string   `json:"stake_currency,omitempty"`
+	Symbols       []string `json:"symbols"`   // pairs to backtest, e.g. "BTC/USDT"
+	Timeframe     string   `json:"timeframe"` // e.g. "5m", "1h"
+
+	// Timerange is the Freqtrade "YYYYMMDD-YYYYMMDD" timerange string. If
+	// empty, it's derived from StartTime/EndTime.
+	Timerange string    `json:"timerange,omitempty"`
+	StartTime time.Time `json:"start_time,omitempty"`
+	EndTime   time.Time `json:"end_time,omitempty"`
+
+	Fee           float64 `json:"fee"` // fallback maker/taker fee when no override applies
+	StakeAmount   float64 `json:"stake_amount"`
+	MaxOpenTrades int     `json:"max_open_trades"`
+
+	// FeeOverrides maps exchange name -> maker/taker fee, keyed the same way
+	// as ExchangeName. Only the entry matching ExchangeName (if any) is applied.
+	FeeOverrides map[string]ExchangeFeeOverride `json:"fee_overrides,omitempty"`
+}
+
+// GenerateBacktestConfig renders spec into a Freqtrade config-backtest.json,
+// the same map[string]interface{} shape runner.BacktestSpec.Config expects.
+func (g *Generator) GenerateBacktestConfig(spec BacktestSpec) ([]byte, error) {
+	if spec.StrategyName == "" {
+		return nil, fmt.Errorf("strategy name is required")
+	}
+	if spec.ExchangeName == "" {
+		return nil, fmt.Errorf("exchange name is required")
+	}
+	if spec.Timeframe == "" {
+		return nil, fmt.Errorf("timeframe is required")
+	}
+	if len(spec.Symbols) == 0 {
+		return nil, fmt.Errorf("at least one symbol is required")
+	}
+	if spec.StakeAmount <= 0 {
+		return nil, fmt.Errorf("stake_amount must be positive")
+	}
+	if spec.MaxOpenTrades <= 0 {
+		return nil, fmt.Errorf("max_open_trades must be positive")
+	}
+
+	timerange, err := backtestTimerange(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := spec.Fee
+	if override, ok := spec.FeeOverrides[spec.ExchangeName]; ok {
+		// Freqtrade models a single round-trip fee; use the taker fee since
+		// backtests fill at market by default.
+		fee = override.Taker
+	}
+
+	stakeCurrency := spec.StakeCurrency
+	if stakeCurrency == "" {
+		stakeCurrency = "USDT"
+	}
+
+	config := map[string]interface{}{
+		"strategy":        spec.StrategyName,
+		"timeframe":       spec.Timeframe,
+		"timerange":       timerange,
+		"stake_currency":  stakeCurrency,
+		"stake_amount":    spec.StakeAmount,
+		"max_open_trades": spec.MaxOpenTrades,
+		"fee":             fee,
+		"dry_run":         true,
+		"exchange": map[string]interface{}{
+			"name":           spec.ExchangeName,
+			"pair_whitelist": spec.Symbols,
+			"pair_blacklist": []string{},
+		},
+	}
+
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// backtestTimerange returns spec.Timerange verbatim if set, otherwise derives
+// Freqtrade's "YYYYMMDD-YYYYMMDD" format from StartTime/EndTime.
+func backtestTimerange(spec BacktestSpec) (string, error) {
+	if spec.Timerange != "" {
+		return spec.Timerange, nil
+	}
+	if spec.StartTime.IsZero() || spec.EndTime.IsZero() {
+		return "", fmt.Errorf("timerange or both start_time and end_time are required")
+	}
+	if !spec.EndTime.After(spec.StartTime) {
+		return "", fmt.Errorf("end_time must be after start_time")
+	}
+	return fmt.Sprintf("%s-%s", spec.StartTime.UTC().Format("20060102"), spec.EndTime.UTC().Format("20060102")), nil
+}
@@ -9,13 +9,18 @@ import (
 type Generator struct {
 	indicators map[string]IndicatorDefinition // ID -> Definition
 	imports    map[string]bool                // Track required imports
+
+	hyperoptParams    []HyperoptParameter // hyperopt parameters registered this pass, in encounter order
+	hyperoptParamSeq  int                 // fallback-name counter for unlabeled hyperopt constants
+	hyperoptParamName map[string]bool     // names already assigned this pass, to keep them unique
 }
 
 // NewGenerator creates a new code generator
 func NewGenerator() *Generator {
 	return &Generator{
-		indicators: make(map[string]IndicatorDefinition),
-		imports:    make(map[string]bool),
+		indicators:        make(map[string]IndicatorDefinition),
+		imports:           make(map[string]bool),
+		hyperoptParamName: make(map[string]bool),
 	}
 }
 
@@ -291,6 +296,12 @@ func (g *Generator) GenerateOperand(op *Operand) (string, error) {
 		return g.generateMarketOperand(op)
 	case OperandTypeCOMPUTED:
 		return g.generateComputedOperand(op)
+	case OperandTypeATRScaled:
+		return g.generateAtrScaledOperand(op)
+	case OperandTypeVolatilityBand:
+		return g.generateVolatilityBandOperand(op)
+	case OperandTypeSCALE:
+		return g.generateScaleOperand(op)
 	default:
 		return "", fmt.Errorf("unsupported operand type: %s", opType)
 	}
@@ -303,6 +314,14 @@ func (g *Generator) generateConstantOperand(op *Operand) (string, error) {
 		return "", err
 	}
 
+	if constOp.Hyperopt != nil {
+		name, err := g.registerHyperoptParam(constOp)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("self.%s.value", name), nil
+	}
+
 	switch v := constOp.Value.(type) {
 	case float64:
 		return fmt.Sprintf("%v", v), nil
@@ -516,6 +535,105 @@ func (g *Generator) generateComputedOperand(op *Operand) (string, error) {
 	}
 }
 
+// generateAtrScaledOperand generates Python code for ATR_SCALED operands:
+// a reference price/indicator offset by a multiple of a rolling ATR, so the
+// threshold adapts to volatility instead of staying fixed across pairs.
+func (g *Generator) generateAtrScaledOperand(op *Operand) (string, error) {
+	atrOp, err := op.AsAtrScaledOperand()
+	if err != nil {
+		return "", err
+	}
+
+	refCode, err := g.GenerateOperand(&atrOp.Reference)
+	if err != nil {
+		return "", err
+	}
+
+	var sign string
+	switch atrOp.Direction {
+	case "above":
+		sign = "+"
+	case "below":
+		sign = "-"
+	default:
+		return "", fmt.Errorf("unknown atr_scaled direction: %s", atrOp.Direction)
+	}
+
+	return fmt.Sprintf("%s %s %v * %s", refCode, sign, atrOp.Multiplier, g.atrExpression(atrOp.Window)), nil
+}
+
+// atrExpression returns the Python expression for a window-period ATR,
+// always computed inline with ta.ATR. A condition tree may legally reference
+// the same window more than once (e.g. the same volatility threshold in an
+// entry and an exit leg); emitting the call inline every time, rather than
+// caching it behind a dataframe['atr_<window>'] column that nothing assigns
+// in populate_indicators(), means every reference is self-contained and
+// never depends on assembly order.
+func (g *Generator) atrExpression(window int) string {
+	g.imports["talib"] = true
+	return fmt.Sprintf("ta.ATR(dataframe, timeperiod=%d)", window)
+}
+
+// generateVolatilityBandOperand generates Python code for VOLATILITY_BAND
+// operands, referencing the requested band of a Bollinger or Keltner channel.
+func (g *Generator) generateVolatilityBandOperand(op *Operand) (string, error) {
+	bandOp, err := op.AsVolatilityBandOperand()
+	if err != nil {
+		return "", err
+	}
+
+	g.imports["qtpylib"] = true
+
+	switch bandOp.Kind {
+	case "bollinger":
+		accessor, err := bollingerBandAccessor(bandOp.Band)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("qtpylib.bollinger_bands(qtpylib.typical_price(dataframe), window=%d, stds=%v)['%s']",
+			bandOp.Window, bandOp.StdDev, accessor), nil
+	case "keltner":
+		accessor, err := keltnerBandAccessor(bandOp.Band)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("qtpylib.keltner_channel(dataframe, window=%d, atrs=%v)['%s']",
+			bandOp.Window, bandOp.StdDev, accessor), nil
+	default:
+		return "", fmt.Errorf("unknown volatility band kind: %s", bandOp.Kind)
+	}
+}
+
+// bollingerBandAccessor maps a VolatilityBandOperand.Band value to the
+// column name qtpylib.bollinger_bands returns it under.
+func bollingerBandAccessor(band string) (string, error) {
+	switch band {
+	case "upper":
+		return "upper", nil
+	case "lower":
+		return "lower", nil
+	case "mid":
+		return "mid", nil
+	default:
+		return "", fmt.Errorf("unknown volatility band: %s", band)
+	}
+}
+
+// keltnerBandAccessor maps a VolatilityBandOperand.Band value to the column
+// name qtpylib.keltner_channel returns it under.
+func keltnerBandAccessor(band string) (string, error) {
+	switch band {
+	case "upper":
+		return "upper", nil
+	case "lower":
+		return "lower", nil
+	case "mid":
+		return "mid", nil
+	default:
+		return "", fmt.Errorf("unknown volatility band: %s", band)
+	}
+}
+
 // GetRequiredImports returns the list of imports needed for the generated code
 func (g *Generator) GetRequiredImports() []string {
 	imports := make([]string, 0, len(g.imports))
@@ -525,7 +643,11 @@ func (g *Generator) GetRequiredImports() []string {
 	return imports
 }
 
-// ResetImports clears the tracked imports
+// ResetImports clears the tracked imports and any hyperopt parameters
+// registered by a prior GenerateHyperoptSpaces pass.
 func (g *Generator) ResetImports() {
 	g.imports = make(map[string]bool)
+	g.hyperoptParams = nil
+	g.hyperoptParamSeq = 0
+	g.hyperoptParamName = make(map[string]bool)
 }
@@ -0,0 +1,171 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HyperoptParameter is a single class-level Freqtrade hyperopt parameter
+// compiled from a ConstantOperand's HyperoptRange annotation.
+type HyperoptParameter struct {
+	Name    string
+	Kind    string // "int", "decimal", or "categorical"
+	Space   string // "buy", "sell", or "protection"
+	Default interface{}
+	Low     float64
+	High    float64
+	Step    float64
+	Choices []interface{}
+}
+
+// HyperoptOutput is the result of GenerateHyperoptSpaces: the condition code
+// with hyperopt-tagged constants rewritten to self.<name>.value, the
+// parameters that were registered, their Python declarations, and the
+// --spaces schema fragment Freqtrade's CLI expects.
+type HyperoptOutput struct {
+	ConditionCode string
+	Parameters    []HyperoptParameter
+	Declarations  []string
+	SpacesSchema  map[string]interface{}
+}
+
+// GenerateHyperoptSpaces walks root, generating its condition code exactly
+// like GenerateCondition while turning every hyperopt-tagged ConstantOperand
+// it encounters into a Freqtrade parameter: the condition code references
+// self.<name>.value in place of the literal, and the parameter's class-level
+// declaration and --spaces schema entry are returned alongside it.
+func (g *Generator) GenerateHyperoptSpaces(root *ConditionNode) (HyperoptOutput, error) {
+	g.hyperoptParams = nil
+	g.hyperoptParamSeq = 0
+	g.hyperoptParamName = make(map[string]bool)
+
+	conditionCode, err := g.GenerateCondition(root)
+	if err != nil {
+		return HyperoptOutput{}, err
+	}
+
+	return HyperoptOutput{
+		ConditionCode: conditionCode,
+		Parameters:    g.hyperoptParams,
+		Declarations:  renderHyperoptDeclarations(g.hyperoptParams),
+		SpacesSchema:  buildSpacesSchema(g.hyperoptParams),
+	}, nil
+}
+
+// registerHyperoptParam records constOp's HyperoptRange as a new parameter
+// and returns the Python attribute name the generated condition should
+// reference it by.
+func (g *Generator) registerHyperoptParam(constOp *ConstantOperand) (string, error) {
+	hr := constOp.Hyperopt
+
+	switch hr.Kind {
+	case "int", "decimal", "categorical":
+	default:
+		return "", fmt.Errorf("unknown hyperopt parameter kind: %s", hr.Kind)
+	}
+	switch hr.Space {
+	case "buy", "sell", "protection":
+	default:
+		return "", fmt.Errorf("unknown hyperopt parameter space: %s", hr.Space)
+	}
+
+	name := g.nextHyperoptParamName(constOp.Label)
+
+	g.hyperoptParams = append(g.hyperoptParams, HyperoptParameter{
+		Name:    name,
+		Kind:    hr.Kind,
+		Space:   hr.Space,
+		Default: constOp.Value,
+		Low:     hr.Low,
+		High:    hr.High,
+		Step:    hr.Step,
+		Choices: hr.Choices,
+	})
+
+	return name, nil
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// nextHyperoptParamName turns label into a unique, valid Python identifier,
+// falling back to a generated name when label is empty or sanitizes away to
+// nothing.
+func (g *Generator) nextHyperoptParamName(label string) string {
+	name := nonIdentifierChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(label)), "_")
+	name = strings.Trim(name, "_")
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = fmt.Sprintf("hp_param_%d", g.hyperoptParamSeq)
+		g.hyperoptParamSeq++
+	}
+
+	for g.hyperoptParamName[name] {
+		name = fmt.Sprintf("%s_%d", name, g.hyperoptParamSeq)
+		g.hyperoptParamSeq++
+	}
+	g.hyperoptParamName[name] = true
+
+	return name
+}
+
+// renderHyperoptDeclarations renders one Python class-attribute declaration
+// per parameter, in the order they were registered.
+func renderHyperoptDeclarations(params []HyperoptParameter) []string {
+	declarations := make([]string, len(params))
+	for i, p := range params {
+		declarations[i] = renderHyperoptDeclaration(p)
+	}
+	return declarations
+}
+
+func renderHyperoptDeclaration(p HyperoptParameter) string {
+	switch p.Kind {
+	case "int":
+		return fmt.Sprintf("%s = IntParameter(%v, %v, default=%v, space=%q, optimize=True)",
+			p.Name, p.Low, p.High, p.Default, p.Space)
+	case "decimal":
+		return fmt.Sprintf("%s = DecimalParameter(%v, %v, default=%v, space=%q, optimize=True)",
+			p.Name, p.Low, p.High, p.Default, p.Space)
+	case "categorical":
+		return fmt.Sprintf("%s = CategoricalParameter(%s, default=%v, space=%q, optimize=True)",
+			p.Name, pythonList(p.Choices), p.Default, p.Space)
+	default:
+		return fmt.Sprintf("# unsupported hyperopt parameter kind %q for %s", p.Kind, p.Name)
+	}
+}
+
+// pythonList renders values as a Python list literal.
+func pythonList(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			parts[i] = fmt.Sprintf("%q", s)
+			continue
+		}
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// buildSpacesSchema returns the --spaces fragment Freqtrade's hyperopt CLI
+// expects: the sorted set of spaces actually used by params, so callers can
+// pass it straight through as `freqtrade hyperopt --spaces <schema.spaces...>`.
+func buildSpacesSchema(params []HyperoptParameter) map[string]interface{} {
+	seen := make(map[string]bool)
+	for _, p := range params {
+		seen[p.Space] = true
+	}
+
+	spaces := make([]string, 0, len(seen))
+	for space := range seen {
+		spaces = append(spaces, space)
+	}
+	sort.Strings(spaces)
+
+	return map[string]interface{}{
+		"$schema": "freqtrade-hyperopt-spaces/v1",
+		"spaces":  spaces,
+	}
+}
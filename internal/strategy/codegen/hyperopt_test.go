@@ -0,0 +1,90 @@
+package codegen
+
+import "testing"
+
+func TestGenerateHyperoptSpaces(t *testing.T) {
+	root := mustParseCondition(t, `{
+		"type": "COMPARE",
+		"operator": "gt",
+		"left": {"type": "INDICATOR", "indicatorId": "rsi_14"},
+		"right": {
+			"type": "CONSTANT",
+			"label": "RSI Threshold",
+			"value": 30,
+			"hyperopt": {"kind": "int", "low": 20, "high": 40, "space": "buy"}
+		}
+	}`)
+
+	g := NewGenerator()
+	output, err := g.GenerateHyperoptSpaces(root)
+	if err != nil {
+		t.Fatalf("GenerateHyperoptSpaces() error = %v", err)
+	}
+
+	wantCondition := "dataframe['rsi_14'] > self.rsi_threshold.value"
+	if output.ConditionCode != wantCondition {
+		t.Errorf("ConditionCode = %q, want %q", output.ConditionCode, wantCondition)
+	}
+
+	if len(output.Parameters) != 1 {
+		t.Fatalf("Parameters = %v, want 1 entry", output.Parameters)
+	}
+	param := output.Parameters[0]
+	if param.Name != "rsi_threshold" || param.Kind != "int" || param.Space != "buy" {
+		t.Errorf("Parameters[0] = %+v", param)
+	}
+
+	wantDecl := `rsi_threshold = IntParameter(20, 40, default=30, space="buy", optimize=True)`
+	if len(output.Declarations) != 1 || output.Declarations[0] != wantDecl {
+		t.Errorf("Declarations = %v, want [%q]", output.Declarations, wantDecl)
+	}
+
+	spaces, ok := output.SpacesSchema["spaces"].([]string)
+	if !ok || len(spaces) != 1 || spaces[0] != "buy" {
+		t.Errorf("SpacesSchema[\"spaces\"] = %v, want [buy]", output.SpacesSchema["spaces"])
+	}
+}
+
+func TestGenerateHyperoptSpaces_UnlabeledConstantGetsFallbackName(t *testing.T) {
+	root := mustParseCondition(t, `{
+		"type": "COMPARE",
+		"operator": "gt",
+		"left": {"type": "PRICE", "field": "close"},
+		"right": {
+			"type": "CONSTANT",
+			"value": 1.5,
+			"hyperopt": {"kind": "decimal", "low": 1.0, "high": 2.0, "space": "sell"}
+		}
+	}`)
+
+	g := NewGenerator()
+	output, err := g.GenerateHyperoptSpaces(root)
+	if err != nil {
+		t.Fatalf("GenerateHyperoptSpaces() error = %v", err)
+	}
+
+	if len(output.Parameters) != 1 {
+		t.Fatalf("Parameters = %v, want 1 entry", output.Parameters)
+	}
+	if output.Parameters[0].Name != "hp_param_0" {
+		t.Errorf("Parameters[0].Name = %q, want hp_param_0", output.Parameters[0].Name)
+	}
+}
+
+func TestGenerateHyperoptSpaces_UnknownKind(t *testing.T) {
+	root := mustParseCondition(t, `{
+		"type": "COMPARE",
+		"operator": "gt",
+		"left": {"type": "PRICE", "field": "close"},
+		"right": {
+			"type": "CONSTANT",
+			"value": 1,
+			"hyperopt": {"kind": "enum", "space": "buy"}
+		}
+	}`)
+
+	g := NewGenerator()
+	if _, err := g.GenerateHyperoptSpaces(root); err == nil {
+		t.Errorf("GenerateHyperoptSpaces() expected error for unknown kind, got none")
+	}
+}
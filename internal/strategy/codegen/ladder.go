@@ -0,0 +1,215 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LadderSpec describes a resolved (numeric) N-layer ladder of values between
+// From and To, ready for GenerateLadder to expand into concrete levels.
+type LadderSpec struct {
+	Layers  int
+	From    float64
+	To      float64
+	Curve   string // "linear", "exp", or "log"
+	ExpBase float64
+}
+
+// ValidateLadderSpec checks that spec describes a well-formed ladder.
+func ValidateLadderSpec(spec LadderSpec) error {
+	if spec.Layers <= 0 {
+		return fmt.Errorf("layers must be positive, got %d", spec.Layers)
+	}
+	if spec.From == spec.To {
+		return fmt.Errorf("from and to must differ")
+	}
+
+	switch spec.Curve {
+	case "linear":
+		return nil
+	case "exp":
+		if spec.ExpBase <= 0 {
+			return fmt.Errorf("exp_base must be positive, got %v", spec.ExpBase)
+		}
+		if spec.ExpBase == 1 {
+			return fmt.Errorf("exp_base of 1 produces a flat ladder")
+		}
+		// From * ExpBase^i moves up when the base grows a positive From or
+		// shrinks a negative one.
+		movesUp := (spec.ExpBase > 1) == (spec.From > 0)
+		rising := spec.To > spec.From
+		if movesUp != rising {
+			return fmt.Errorf("exp_base %v does not move from %v toward %v", spec.ExpBase, spec.From, spec.To)
+		}
+		return nil
+	case "log":
+		if spec.From <= 0 || spec.To <= 0 {
+			return fmt.Errorf("from and to must be positive for a log curve")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown ladder curve: %s", spec.Curve)
+	}
+}
+
+// GenerateLadder resolves op's From/To constants into a LadderSpec and
+// returns its Layers levels as Python literals, from the innermost layer
+// (closest to From) to the outermost (closest to To).
+func (g *Generator) GenerateLadder(op *ScaleOperand) ([]string, error) {
+	from, err := constantOperandFloat(&op.From)
+	if err != nil {
+		return nil, fmt.Errorf("ladder from: %w", err)
+	}
+	to, err := constantOperandFloat(&op.To)
+	if err != nil {
+		return nil, fmt.Errorf("ladder to: %w", err)
+	}
+
+	spec := LadderSpec{
+		Layers:  op.Layers,
+		From:    from,
+		To:      to,
+		Curve:   op.Curve,
+		ExpBase: op.ExpBase,
+	}
+	if err := ValidateLadderSpec(spec); err != nil {
+		return nil, err
+	}
+
+	levels := make([]float64, spec.Layers)
+	switch spec.Curve {
+	case "linear":
+		if spec.Layers == 1 {
+			levels[0] = spec.From
+			break
+		}
+		step := (spec.To - spec.From) / float64(spec.Layers-1)
+		for i := range levels {
+			levels[i] = spec.From + step*float64(i)
+		}
+	case "exp":
+		for i := range levels {
+			levels[i] = spec.From * math.Pow(spec.ExpBase, float64(i))
+		}
+	case "log":
+		logFrom := math.Log(spec.From)
+		logTo := math.Log(spec.To)
+		if spec.Layers == 1 {
+			levels[0] = spec.From
+			break
+		}
+		step := (logTo - logFrom) / float64(spec.Layers-1)
+		for i := range levels {
+			levels[i] = math.Exp(logFrom + step*float64(i))
+		}
+	}
+
+	literals := make([]string, len(levels))
+	for i, v := range levels {
+		literals[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return literals, nil
+}
+
+// generateScaleOperand generates a Python list literal for a SCALE operand
+// used directly in a condition tree, e.g. to compare a value against the
+// full set of ladder levels rather than through GeneratePopulateEntryTrend.
+func (g *Generator) generateScaleOperand(op *Operand) (string, error) {
+	scaleOp, err := op.AsScaleOperand()
+	if err != nil {
+		return "", err
+	}
+
+	levels, err := g.GenerateLadder(scaleOp)
+	if err != nil {
+		return "", err
+	}
+
+	return "[" + strings.Join(levels, ", ") + "]", nil
+}
+
+// constantOperandFloat extracts a numeric value from a CONSTANT operand,
+// the only operand kind GenerateLadder can resolve into a literal level.
+func constantOperandFloat(op *Operand) (float64, error) {
+	opType, err := op.GetOperandType()
+	if err != nil {
+		return 0, err
+	}
+	if opType != OperandTypeCONSTANT {
+		return 0, fmt.Errorf("expected a CONSTANT operand, got %s", opType)
+	}
+	constOp, err := op.AsConstantOperand()
+	if err != nil {
+		return 0, err
+	}
+	switch v := constOp.Value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("constant operand value is not numeric: %v", constOp.Value)
+	}
+}
+
+// constantOperand wraps value as a CONSTANT Operand.
+func constantOperand(value float64) Operand {
+	op := ConstantOperand{
+		BaseOperand: BaseOperand{Type: OperandTypeCONSTANT},
+		Value:       value,
+		ValueType:   "number",
+	}
+	raw, err := json.Marshal(op)
+	if err != nil {
+		// Marshaling a struct of plain fields cannot fail.
+		panic(err)
+	}
+	return Operand{raw: raw}
+}
+
+// GeneratePopulateEntryTrend compiles ladder into the bodies of Freqtrade's
+// custom_entry_price and adjust_trade_position callbacks: a bid at proposed_rate
+// that steps through ladder.Layers price offsets from -RangePercent to
+// +RangePercent as repeat entries come in. Each layer's stake is the base stake
+// times ExpBase**layer for an "exp" curve, and the same for every layer otherwise.
+func (g *Generator) GeneratePopulateEntryTrend(ladder LadderConfig) (customEntryPrice, adjustTradePosition string, err error) {
+	from := constantOperand(-ladder.RangePercent)
+	to := constantOperand(ladder.RangePercent)
+	scaleOp := &ScaleOperand{
+		BaseOperand: BaseOperand{Type: OperandTypeSCALE},
+		From:        from,
+		To:          to,
+		Layers:      ladder.Layers,
+		Curve:       ladder.Curve,
+		ExpBase:     ladder.ExpBase,
+	}
+
+	offsets, err := g.GenerateLadder(scaleOp)
+	if err != nil {
+		return "", "", fmt.Errorf("ladder: %w", err)
+	}
+
+	offsetList := "[" + strings.Join(offsets, ", ") + "]"
+
+	customEntryPrice = fmt.Sprintf(`ladder_offsets = %s
+layer = min(self.ladder_layer.get(pair, 0), len(ladder_offsets) - 1)
+return proposed_rate * (1 + ladder_offsets[layer])`, offsetList)
+
+	growthExpr := "1"
+	if ladder.Curve == "exp" {
+		growthExpr = fmt.Sprintf("%v ** layer", ladder.ExpBase)
+	}
+
+	adjustTradePosition = fmt.Sprintf(`layer = min(trade.nr_of_successful_entries, %d - 1)
+if trade.nr_of_successful_entries >= %d:
+    return None
+self.ladder_layer[trade.pair] = trade.nr_of_successful_entries
+growth = %s
+stake = min_stake * growth if min_stake else trade.stake_amount * growth
+return stake`, ladder.Layers, ladder.Layers, growthExpr)
+
+	return customEntryPrice, adjustTradePosition, nil
+}
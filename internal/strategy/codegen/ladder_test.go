@@ -0,0 +1,158 @@
+package codegen
+
+import "testing"
+
+func TestValidateLadderSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    LadderSpec
+		wantErr bool
+	}{
+		{
+			name: "valid linear",
+			spec: LadderSpec{Layers: 5, From: -0.01, To: -0.05, Curve: "linear"},
+		},
+		{
+			name:    "zero layers",
+			spec:    LadderSpec{Layers: 0, From: 1, To: 2, Curve: "linear"},
+			wantErr: true,
+		},
+		{
+			name:    "equal from and to",
+			spec:    LadderSpec{Layers: 3, From: 1, To: 1, Curve: "linear"},
+			wantErr: true,
+		},
+		{
+			name:    "exp base of 1 is flat",
+			spec:    LadderSpec{Layers: 3, From: 1, To: 2, Curve: "exp", ExpBase: 1},
+			wantErr: true,
+		},
+		{
+			name:    "exp base direction mismatch",
+			spec:    LadderSpec{Layers: 3, From: 1, To: 2, Curve: "exp", ExpBase: 0.5},
+			wantErr: true,
+		},
+		{
+			name: "exp base valid growth",
+			spec: LadderSpec{Layers: 3, From: 1, To: 4, Curve: "exp", ExpBase: 1.5},
+		},
+		{
+			name:    "log with non-positive from",
+			spec:    LadderSpec{Layers: 3, From: 0, To: 10, Curve: "log"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown curve",
+			spec:    LadderSpec{Layers: 3, From: 1, To: 2, Curve: "sawtooth"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLadderSpec(tt.spec)
+			if tt.wantErr && err == nil {
+				t.Errorf("ValidateLadderSpec() expected error, got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateLadderSpec() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateLadder(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       *ScaleOperand
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name: "linear 3 layers",
+			op: &ScaleOperand{
+				From:   constantOperand(0),
+				To:     constantOperand(10),
+				Layers: 3,
+				Curve:  "linear",
+			},
+			expected: []string{"0", "5", "10"},
+		},
+		{
+			name: "exp 3 layers base 2",
+			op: &ScaleOperand{
+				From:    constantOperand(1),
+				To:      constantOperand(4),
+				Layers:  3,
+				Curve:   "exp",
+				ExpBase: 2,
+			},
+			expected: []string{"1", "2", "4"},
+		},
+		{
+			name: "log 3 layers",
+			op: &ScaleOperand{
+				From:   constantOperand(1),
+				To:     constantOperand(100),
+				Layers: 3,
+				Curve:  "log",
+			},
+			expected: []string{"1", "10", "100"},
+		},
+		{
+			name: "non-constant from is rejected",
+			op: &ScaleOperand{
+				From:   mustParseOperand(t, `{"type": "PRICE", "field": "close"}`),
+				To:     constantOperand(10),
+				Layers: 3,
+				Curve:  "linear",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator()
+			levels, err := g.GenerateLadder(tt.op)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GenerateLadder() expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateLadder() error = %v", err)
+			}
+			if len(levels) != len(tt.expected) {
+				t.Fatalf("GenerateLadder() = %v, want %v", levels, tt.expected)
+			}
+			for i, v := range levels {
+				if v != tt.expected[i] {
+					t.Errorf("GenerateLadder()[%d] = %q, want %q", i, v, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePopulateEntryTrend(t *testing.T) {
+	g := NewGenerator()
+	ladder := LadderConfig{
+		Enabled:      true,
+		Layers:       3,
+		RangePercent: 0.02,
+		Curve:        "linear",
+	}
+
+	customEntryPrice, adjustTradePosition, err := g.GeneratePopulateEntryTrend(ladder)
+	if err != nil {
+		t.Fatalf("GeneratePopulateEntryTrend() error = %v", err)
+	}
+	if customEntryPrice == "" {
+		t.Errorf("GeneratePopulateEntryTrend() returned empty custom_entry_price body")
+	}
+	if adjustTradePosition == "" {
+		t.Errorf("GeneratePopulateEntryTrend() returned empty adjust_trade_position body")
+	}
+}
@@ -50,14 +50,17 @@ const (
 
 // OperandType constants (aliases for model.OperandType*)
 const (
-	OperandTypeCONSTANT     = model.OperandTypeConstant
-	OperandTypeINDICATOR    = model.OperandTypeIndicator
-	OperandTypePRICE        = model.OperandTypePrice
-	OperandTypeTradeContext = model.OperandTypeTradeContext
-	OperandTypeTIME         = model.OperandTypeTime
-	OperandTypeEXTERNAL     = model.OperandTypeExternal
-	OperandTypeCOMPUTED     = model.OperandTypeComputed
-	OperandTypeCUSTOM       = model.OperandTypeCustom
+	OperandTypeCONSTANT       = model.OperandTypeConstant
+	OperandTypeINDICATOR      = model.OperandTypeIndicator
+	OperandTypePRICE          = model.OperandTypePrice
+	OperandTypeTradeContext   = model.OperandTypeTradeContext
+	OperandTypeTIME           = model.OperandTypeTime
+	OperandTypeEXTERNAL       = model.OperandTypeExternal
+	OperandTypeCOMPUTED       = model.OperandTypeComputed
+	OperandTypeCUSTOM         = model.OperandTypeCustom
+	OperandTypeATRScaled      = model.OperandTypeAtrScaled
+	OperandTypeVolatilityBand = model.OperandTypeVolatilityBand
+	OperandTypeSCALE          = model.OperandTypeScale
 )
 
 // ComparisonOperator constants (aliases for model.ComparisonOperator*)
@@ -406,6 +409,33 @@ func (o *Operand) AsCustomOperand() (*CustomOperand, error) {
 	return &op, nil
 }
 
+// AsAtrScaledOperand parses the operand as an ATR_SCALED
+func (o *Operand) AsAtrScaledOperand() (*AtrScaledOperand, error) {
+	var op AtrScaledOperand
+	if err := json.Unmarshal(o.raw, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// AsVolatilityBandOperand parses the operand as a VOLATILITY_BAND
+func (o *Operand) AsVolatilityBandOperand() (*VolatilityBandOperand, error) {
+	var op VolatilityBandOperand
+	if err := json.Unmarshal(o.raw, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// AsScaleOperand parses the operand as a SCALE
+func (o *Operand) AsScaleOperand() (*ScaleOperand, error) {
+	var op ScaleOperand
+	if err := json.Unmarshal(o.raw, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
 // BaseOperand contains fields common to all operands
 type BaseOperand struct {
 	Type     OperandType     `json:"type"`
@@ -416,8 +446,22 @@ type BaseOperand struct {
 // ConstantOperand represents a literal value
 type ConstantOperand struct {
 	BaseOperand
-	Value     interface{} `json:"value"`               // number, string, or boolean
-	ValueType string      `json:"valueType,omitempty"` // number, percent, string, boolean, duration, currency
+	Value     interface{}    `json:"value"`               // number, string, or boolean
+	ValueType string         `json:"valueType,omitempty"` // number, percent, string, boolean, duration, currency
+	Hyperopt  *HyperoptRange `json:"hyperopt,omitempty"`  // if set, this value is tunable via GenerateHyperoptSpaces
+}
+
+// HyperoptRange annotates a ConstantOperand so GenerateHyperoptSpaces turns
+// its literal value into a Freqtrade hyperopt parameter instead of inlining
+// it, referencing self.<name>.value in the generated condition wherever the
+// constant would otherwise have appeared.
+type HyperoptRange struct {
+	Kind    string        `json:"kind"` // "int", "decimal", or "categorical"
+	Low     float64       `json:"low,omitempty"`
+	High    float64       `json:"high,omitempty"`
+	Step    float64       `json:"step,omitempty"`
+	Choices []interface{} `json:"choices,omitempty"`
+	Space   string        `json:"space"` // "buy", "sell", or "protection"
 }
 
 // IndicatorOperand references a configured technical indicator
@@ -472,6 +516,40 @@ type CustomOperand struct {
 	Config   map[string]interface{} `json:"config"`
 }
 
+// AtrScaledOperand represents a threshold set as a multiple of a rolling ATR
+// away from a reference price, so the threshold self-adjusts to volatility
+// instead of staying fixed across regime changes.
+type AtrScaledOperand struct {
+	BaseOperand
+	Reference  Operand `json:"reference"`
+	Multiplier float64 `json:"multiplier"`
+	Window     int     `json:"window"`
+	Direction  string  `json:"direction"` // "above" or "below"
+}
+
+// VolatilityBandOperand references a Bollinger/Keltner band accessor, for
+// conditions expressed relative to a volatility channel rather than a fixed
+// indicator value.
+type VolatilityBandOperand struct {
+	BaseOperand
+	Kind   string  `json:"kind"` // "bollinger" or "keltner"
+	Window int     `json:"window"`
+	StdDev float64 `json:"stdDev"`
+	Band   string  `json:"band"` // "upper", "lower", or "mid"
+}
+
+// ScaleOperand describes an N-layer ladder of values between From and To,
+// used to place multiple orders (or size multiple entries) around a
+// reference price instead of a single fixed level.
+type ScaleOperand struct {
+	BaseOperand
+	From    Operand `json:"from"`
+	To      Operand `json:"to"`
+	Layers  int     `json:"layers"`
+	Curve   string  `json:"curve"`             // "linear", "exp", or "log"
+	ExpBase float64 `json:"expBase,omitempty"` // growth factor per layer; required for "exp"
+}
+
 // IndicatorDefinition defines a technical indicator instance
 type IndicatorDefinition struct {
 	ID     string                 `json:"id"`     // Unique ID for reference
@@ -542,6 +620,20 @@ type CallbacksConfig struct {
 	ConfirmEntry   *ConfirmEntryConfig   `json:"confirm_entry,omitempty"`
 	DCA            *DCAConfig            `json:"dca,omitempty"`
 	CustomExit     *CustomExitConfig     `json:"custom_exit,omitempty"`
+	Ladder         *LadderConfig         `json:"ladder,omitempty"`
+}
+
+// LadderConfig configures an N-layer bid ladder of price offsets spanning
+// ±RangePercent around the proposed entry rate, compiled by
+// GeneratePopulateEntryTrend into the
+// custom_entry_price (price offsets) and adjust_trade_position (per-layer
+// stake sizing) callbacks.
+type LadderConfig struct {
+	Enabled      bool    `json:"enabled"`
+	Layers       int     `json:"layers"`
+	RangePercent float64 `json:"range_percent"` // e.g. 0.02 for offsets from -2% to +2%
+	Curve        string  `json:"curve"`         // "linear", "exp", or "log"
+	ExpBase      float64 `json:"exp_base,omitempty"`
 }
 
 // CustomStoplossConfig defines dynamic stoploss rules
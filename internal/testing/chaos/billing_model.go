@@ -0,0 +1,90 @@
+package chaos
+
+import "volaticloud/internal/enum"
+
+// BillingCall is the Call payload recorded for a billing operation.
+type BillingCall struct {
+	OwnerID string
+	Op      string // "change_plan" or "cancel_at_end"
+}
+
+// BillingReturn is the Return payload recorded for a billing operation.
+type BillingReturn struct {
+	Status enum.StripeSubStatus
+}
+
+// billingState tracks, per owner, the subscription status a linearization
+// has committed to so far.
+type billingState map[string]enum.StripeSubStatus
+
+// billingModel is the sequential specification for subscription management:
+// at most one active subscription per owner, and status only moves forward
+// through active -> canceling -> canceled.
+type billingModel struct{}
+
+// NewBillingModel returns a Model encoding the invariants
+// billing.ChangeSubscriptionPlan and billing.CancelSubscriptionAtEnd must
+// uphold even when called concurrently or retried.
+func NewBillingModel() Model {
+	return billingModel{}
+}
+
+func (billingModel) Init() interface{} {
+	return billingState{}
+}
+
+func (billingModel) Step(state interface{}, op Operation) (bool, interface{}) {
+	s := state.(billingState)
+	call, ok := op.Call.(BillingCall)
+	if !ok {
+		return false, state
+	}
+
+	// A failed call observes state but commits nothing new.
+	if op.Err != nil {
+		return true, s
+	}
+
+	ret, ok := op.Return.(BillingReturn)
+	if !ok {
+		return false, state
+	}
+
+	current, existed := s[call.OwnerID]
+	if existed && !validBillingTransition(current, ret.Status) {
+		return false, state
+	}
+
+	next := cloneBillingState(s)
+	next[call.OwnerID] = ret.Status
+	return true, next
+}
+
+// validBillingTransition reports whether moving from `from` to `to` respects
+// the monotonic active -> canceling -> canceled lifecycle. Re-observing the
+// same status (e.g. a retried call) is always legal.
+func validBillingTransition(from, to enum.StripeSubStatus) bool {
+	if from == to {
+		return true
+	}
+	switch from {
+	case enum.StripeSubActive:
+		return to == enum.StripeSubCanceling || to == enum.StripeSubCanceled || to == enum.StripeSubPastDue
+	case enum.StripeSubPastDue:
+		return to == enum.StripeSubActive || to == enum.StripeSubCanceling || to == enum.StripeSubCanceled
+	case enum.StripeSubCanceling:
+		return to == enum.StripeSubCanceled
+	case enum.StripeSubCanceled:
+		return false // terminal — no resurrecting a canceled subscription in place
+	default:
+		return true
+	}
+}
+
+func cloneBillingState(s billingState) billingState {
+	next := make(billingState, len(s))
+	for k, v := range s {
+		next[k] = v
+	}
+	return next
+}
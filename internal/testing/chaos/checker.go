@@ -0,0 +1,91 @@
+package chaos
+
+// Violation describes why a History could not be linearized against a
+// Model: the longest sequential prefix the checker managed to build, and the
+// operation that no longer admitted a legal continuation.
+type Violation struct {
+	// Linearization is the prefix of operations, in the order applied, that
+	// the checker successfully linearized before getting stuck.
+	Linearization []Operation
+	// Offending is the operation for which no remaining interleaving of the
+	// rest of the history could be linearized.
+	Offending Operation
+}
+
+// CheckLinearizable searches for an ordering of history consistent with
+// real-time order (operations that don't overlap must keep their recorded
+// order) for which every operation's Step succeeds against model in
+// sequence. It returns true if one exists; otherwise it returns the deepest
+// partial linearization reached as a Violation, useful for reporting the
+// offending interleaving.
+//
+// This performs an exhaustive backtracking search, in the spirit of the
+// Wing & Gong / Porcupine algorithm. It is intended for the small histories
+// a chaos run accumulates in a test, not for production-scale traces.
+func CheckLinearizable(history History, model Model) (bool, *Violation) {
+	remaining := make(History, len(history))
+	copy(remaining, history)
+
+	linearization := make([]Operation, 0, len(history))
+	ok, violation := search(remaining, model.Init(), model, &linearization)
+	if ok {
+		return true, nil
+	}
+	return false, violation
+}
+
+func search(remaining History, state interface{}, model Model, linearization *[]Operation) (bool, *Violation) {
+	if len(remaining) == 0 {
+		return true, nil
+	}
+
+	var best *Violation
+
+	for i, op := range remaining {
+		if hasPredecessor(remaining, i) {
+			continue // another remaining op must happen first in real time
+		}
+
+		ok, next := model.Step(state, op)
+		if !ok {
+			if best == nil || len(*linearization) > len(best.Linearization) {
+				snapshot := append([]Operation(nil), *linearization...)
+				best = &Violation{Linearization: snapshot, Offending: op}
+			}
+			continue
+		}
+
+		rest := without(remaining, i)
+		*linearization = append(*linearization, op)
+		if done, v := search(rest, next, model, linearization); done {
+			return true, nil
+		} else if v != nil && (best == nil || len(v.Linearization) > len(best.Linearization)) {
+			best = v
+		}
+		*linearization = (*linearization)[:len(*linearization)-1]
+	}
+
+	return false, best
+}
+
+// hasPredecessor reports whether any other operation in remaining must
+// precede remaining[i] in real time, i.e. ended before remaining[i] started.
+func hasPredecessor(remaining History, i int) bool {
+	candidate := remaining[i]
+	for j, other := range remaining {
+		if j == i {
+			continue
+		}
+		if !other.End.After(candidate.Start) {
+			return true
+		}
+	}
+	return false
+}
+
+func without(history History, i int) History {
+	out := make(History, 0, len(history)-1)
+	out = append(out, history[:i]...)
+	out = append(out, history[i+1:]...)
+	return out
+}
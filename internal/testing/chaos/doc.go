@@ -0,0 +1,53 @@
+// Package chaos drives concurrent operations against stateful subsystems
+// while injecting failpoints, then checks the recorded history for
+// linearizability against a sequential model of the subsystem.
+//
+// Today this is implemented end-to-end for only one subsystem: RunnerDriver
+// drives create/start/stop/restart/delete against a real runner.Runtime.
+// NewBillingModel encodes the sequential specification
+// billing.ChangeSubscriptionPlan/CancelSubscriptionAtEnd must uphold ("at
+// most one active subscription per owner", monotonic status transitions),
+// and CheckLinearizable can check a History against it, but there is no
+// BillingDriver yet that calls the real billing package and records its own
+// History — billing.ChangeSubscriptionPlan/CancelSubscriptionAtEnd call the
+// Stripe SDK's package-level functions directly rather than through an
+// injectable client, so driving them for real needs a fake Stripe backend
+// first. Until that exists, billingModel is exercised with hand-built
+// History fixtures (see checker_test.go) rather than a live driver. There is
+// likewise no driver for contextutil runtime storage.
+//
+// # Recording a history
+//
+// A Recorder wraps concurrent calls and timestamps their invocation and
+// completion, producing a History — the same (call, return, err, timestamp)
+// shape Porcupine-style checkers consume:
+//
+//	rec := chaos.NewRecorder()
+//	rec.Do(clientID, "StartBot", botID, func() (interface{}, error) {
+//		return nil, runtime.StartBot(ctx, botID)
+//	})
+//	history := rec.History()
+//
+// # Checking linearizability
+//
+// A Model encodes the sequential specification an implementation must
+// respect. CheckLinearizable searches for an interleaving of the recorded
+// history consistent with real-time order that the model accepts; if none
+// exists, it returns the offending interleaving as a Violation.
+//
+//	ok, violation := chaos.CheckLinearizable(history, chaos.NewRunnerModel())
+//	if !ok {
+//		chaos.WriteReport(os.Stdout, violation)
+//	}
+//
+// # Failpoints
+//
+// A FailpointInjector lets drivers simulate failures mid-operation, so the
+// recorded history includes the partial-failure interleavings that cause
+// bugs like duplicated bot starts under restart storms. RunnerDriver
+// currently injects FailpointDockerHiccup and FailpointProcessKill.
+// FailpointNetworkPartition and FailpointDBConnDrop are defined for drivers
+// that don't exist yet (a BillingDriver would use the former for
+// Stripe/Keycloak partitions; a persistence-layer driver, the latter) —
+// they're not wired into anything today.
+package chaos
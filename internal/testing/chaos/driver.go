@@ -0,0 +1,81 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+
+	"volaticloud/internal/enum"
+	"volaticloud/internal/runner"
+)
+
+// RunnerDriver drives create/start/stop/restart/delete calls against a
+// runner.Runtime, injecting failpoints and recording a History whose
+// operations are consumable by NewRunnerModel.
+type RunnerDriver struct {
+	Runtime  runner.Runtime
+	Recorder *Recorder
+	Injector FailpointInjector
+	ClientID int
+}
+
+// NewRunnerDriver builds a RunnerDriver. injector may be nil, in which case
+// no failpoints fire.
+func NewRunnerDriver(rt runner.Runtime, rec *Recorder, clientID int, injector FailpointInjector) *RunnerDriver {
+	if injector == nil {
+		injector = NoopInjector{}
+	}
+	return &RunnerDriver{Runtime: rt, Recorder: rec, Injector: injector, ClientID: clientID}
+}
+
+// Create provisions spec.ID, recording enum.BotStatusCreating on success.
+func (d *RunnerDriver) Create(ctx context.Context, spec runner.BotSpec) {
+	call := RunnerCall{BotID: spec.ID, Op: "create"}
+	d.Recorder.Do(d.ClientID, "CreateBot", call, func() (interface{}, error) {
+		if err := d.Injector.Inject(FailpointDockerHiccup); err != nil {
+			return RunnerReturn{Status: enum.BotStatusError}, err
+		}
+		if err := d.Runtime.CreateBot(ctx, spec); err != nil {
+			return nil, err
+		}
+		return RunnerReturn{Status: enum.BotStatusCreating}, nil
+	})
+}
+
+// Start starts botID, recording enum.BotStatusRunning on success.
+func (d *RunnerDriver) Start(ctx context.Context, botID string) {
+	d.transition(ctx, botID, "start", enum.BotStatusRunning, d.Runtime.StartBot)
+}
+
+// Stop stops botID, recording enum.BotStatusStopped on success.
+func (d *RunnerDriver) Stop(ctx context.Context, botID string) {
+	d.transition(ctx, botID, "stop", enum.BotStatusStopped, d.Runtime.StopBot)
+}
+
+// Restart restarts botID, recording enum.BotStatusRunning on success.
+func (d *RunnerDriver) Restart(ctx context.Context, botID string) {
+	d.transition(ctx, botID, "restart", enum.BotStatusRunning, d.Runtime.RestartBot)
+}
+
+// Delete removes botID.
+func (d *RunnerDriver) Delete(ctx context.Context, botID string) {
+	call := RunnerCall{BotID: botID, Op: "delete"}
+	d.Recorder.Do(d.ClientID, "DeleteBot", call, func() (interface{}, error) {
+		if err := d.Injector.Inject(FailpointProcessKill); err != nil {
+			return nil, err
+		}
+		return nil, d.Runtime.DeleteBot(ctx, botID)
+	})
+}
+
+func (d *RunnerDriver) transition(ctx context.Context, botID, op string, onSuccess enum.BotStatus, fn func(context.Context, string) error) {
+	call := RunnerCall{BotID: botID, Op: op}
+	d.Recorder.Do(d.ClientID, fmt.Sprintf("%sBot", op), call, func() (interface{}, error) {
+		if err := d.Injector.Inject(FailpointDockerHiccup); err != nil {
+			return nil, err
+		}
+		if err := fn(ctx, botID); err != nil {
+			return nil, err
+		}
+		return RunnerReturn{Status: onSuccess}, nil
+	})
+}
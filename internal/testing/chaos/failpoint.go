@@ -0,0 +1,58 @@
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Failpoint names understood by the built-in drivers. Custom drivers may
+// define their own. RunnerDriver injects FailpointDockerHiccup and
+// FailpointProcessKill; FailpointNetworkPartition and FailpointDBConnDrop
+// are reserved for a BillingDriver/persistence-layer driver that don't
+// exist yet (see doc.go) and aren't injected by anything today.
+const (
+	FailpointProcessKill      = "process-kill"
+	FailpointNetworkPartition = "network-partition"
+	FailpointDBConnDrop       = "db-conn-drop"
+	FailpointDockerHiccup     = "docker-hiccup"
+)
+
+// FailpointInjector decides whether a named failpoint should fire on a given
+// invocation, letting drivers simulate process kills, network partitions to
+// external services, DB connection drops, and daemon hiccups mid-operation.
+type FailpointInjector interface {
+	// Inject returns a non-nil error if the named failpoint fires this time.
+	Inject(name string) error
+}
+
+// ProbabilisticInjector fires each named failpoint independently with a
+// fixed probability, using the given PRNG so runs are reproducible with a
+// seeded rand.Rand.
+type ProbabilisticInjector struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	rates map[string]float64
+}
+
+// NewProbabilisticInjector builds an injector that fires failpoint name with
+// probability rates[name] (0 if absent), using rng for draws.
+func NewProbabilisticInjector(rng *rand.Rand, rates map[string]float64) *ProbabilisticInjector {
+	return &ProbabilisticInjector{rng: rng, rates: rates}
+}
+
+func (p *ProbabilisticInjector) Inject(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.rng.Float64() < p.rates[name] {
+		return fmt.Errorf("chaos: injected failpoint %q", name)
+	}
+	return nil
+}
+
+// NoopInjector never fires. It is the zero-value-friendly default for
+// drivers run without chaos injection.
+type NoopInjector struct{}
+
+func (NoopInjector) Inject(string) error { return nil }
@@ -0,0 +1,73 @@
+package chaos
+
+import (
+	"sync"
+	"time"
+)
+
+// Operation is a single recorded (call, return, err, timestamp) tuple.
+// Call/Return carry opaque, model-specific payloads — the Model decides how
+// to interpret them.
+type Operation struct {
+	ClientID int
+	Name     string
+	Call     interface{}
+	Return   interface{}
+	Err      error
+	Start    time.Time
+	End      time.Time
+}
+
+// Overlaps reports whether o and other were concurrent — i.e. neither
+// happened strictly before the other in real time. Only overlapping
+// operations are free to be reordered by a linearization.
+func (o Operation) Overlaps(other Operation) bool {
+	return o.Start.Before(other.End) && other.Start.Before(o.End)
+}
+
+// History is a recorded sequence of operations; Recorder appends each one
+// when it completes. Operations may overlap in time.
+type History []Operation
+
+// Recorder timestamps concurrent operations as they happen, producing a
+// History safe to build from multiple goroutines.
+type Recorder struct {
+	mu  sync.Mutex
+	ops History
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Do runs fn, recording its invocation and completion time along with the
+// call payload, returned value, and error. It is safe to call concurrently
+// from multiple goroutines playing the role of clientID.
+func (r *Recorder) Do(clientID int, name string, call interface{}, fn func() (interface{}, error)) {
+	start := time.Now()
+	ret, err := fn()
+	end := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, Operation{
+		ClientID: clientID,
+		Name:     name,
+		Call:     call,
+		Return:   ret,
+		Err:      err,
+		Start:    start,
+		End:      end,
+	})
+}
+
+// History returns a copy of the operations recorded so far, ordered by
+// completion time.
+func (r *Recorder) History() History {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(History, len(r.ops))
+	copy(out, r.ops)
+	return out
+}
@@ -0,0 +1,16 @@
+package chaos
+
+// Model is a sequential specification for a subsystem: given the model's
+// current state and one recorded operation, Step reports whether applying
+// that operation in this position is legal, and if so, the resulting state.
+// CheckLinearizable searches for an ordering of a History for which every
+// operation's Step succeeds in sequence.
+type Model interface {
+	// Init returns the model's initial state.
+	Init() interface{}
+
+	// Step applies op to state and reports whether the operation's recorded
+	// Call/Return/Err is consistent with having run, alone, against state at
+	// this point in some sequential execution. It must not mutate state.
+	Step(state interface{}, op Operation) (ok bool, next interface{})
+}
@@ -0,0 +1,44 @@
+package chaos
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteReport renders v as a Markdown report describing the offending
+// interleaving: the prefix that linearized cleanly, followed by the
+// operation that broke the model.
+func WriteReport(w io.Writer, v *Violation) error {
+	if v == nil {
+		_, err := io.WriteString(w, "# Linearizability check passed\n\nNo violation found.\n")
+		return err
+	}
+
+	fmt.Fprintln(w, "# Linearizability violation")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Found a history that cannot be linearized. %d operation(s) linearized cleanly before the model rejected the next one.\n\n", len(v.Linearization))
+
+	fmt.Fprintln(w, "## Linearized prefix")
+	fmt.Fprintln(w)
+	if len(v.Linearization) == 0 {
+		fmt.Fprintln(w, "_(none — the very first operation already violated the model)_")
+	} else {
+		fmt.Fprintln(w, "| # | Client | Op | Call | Return | Err |")
+		fmt.Fprintln(w, "|---|--------|----|------|--------|-----|")
+		for i, op := range v.Linearization {
+			fmt.Fprintf(w, "| %d | %d | %s | %v | %v | %v |\n", i+1, op.ClientID, op.Name, op.Call, op.Return, op.Err)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Offending operation")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "- Client: `%d`\n", v.Offending.ClientID)
+	fmt.Fprintf(w, "- Op: `%s`\n", v.Offending.Name)
+	fmt.Fprintf(w, "- Call: `%v`\n", v.Offending.Call)
+	fmt.Fprintf(w, "- Return: `%v`\n", v.Offending.Return)
+	fmt.Fprintf(w, "- Err: `%v`\n", v.Offending.Err)
+	fmt.Fprintf(w, "- Window: `%s` → `%s`\n", v.Offending.Start.Format("15:04:05.000000"), v.Offending.End.Format("15:04:05.000000"))
+
+	return nil
+}
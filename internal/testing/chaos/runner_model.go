@@ -0,0 +1,102 @@
+package chaos
+
+import "volaticloud/internal/enum"
+
+// RunnerCall is the Call payload recorded for a runner.Runtime operation.
+type RunnerCall struct {
+	BotID string
+	Op    string // "create", "start", "stop", "restart", "delete"
+}
+
+// RunnerReturn is the Return payload recorded for a runner.Runtime
+// operation whose status changed.
+type RunnerReturn struct {
+	Status enum.BotStatus
+}
+
+type runnerState map[string]enum.BotStatus
+
+// runnerLifecycleEdges is the DAG of legal container lifecycle transitions.
+// A bot that doesn't yet appear in the state is implicitly "absent", which
+// only CreateBot may leave.
+var runnerLifecycleEdges = map[enum.BotStatus][]enum.BotStatus{
+	enum.BotStatusCreating:    {enum.BotStatusRunning, enum.BotStatusError},
+	enum.BotStatusRunning:     {enum.BotStatusStopped, enum.BotStatusUnhealthy, enum.BotStatusError},
+	enum.BotStatusUnhealthy:   {enum.BotStatusRunning, enum.BotStatusStopped, enum.BotStatusError},
+	enum.BotStatusStopped:     {enum.BotStatusRunning, enum.BotStatusCreating},
+	enum.BotStatusError:       {enum.BotStatusStopped, enum.BotStatusCreating},
+	enum.BotStatusBacktesting: {enum.BotStatusStopped, enum.BotStatusError},
+	enum.BotStatusHyperopt:    {enum.BotStatusStopped, enum.BotStatusError},
+}
+
+type runnerModel struct{}
+
+// NewRunnerModel returns a Model encoding the container lifecycle as a DAG,
+// so a chaos run catches things like duplicated bot starts or a delete
+// racing a restart from leaving a bot in an impossible state.
+func NewRunnerModel() Model {
+	return runnerModel{}
+}
+
+func (runnerModel) Init() interface{} {
+	return runnerState{}
+}
+
+func (runnerModel) Step(state interface{}, op Operation) (bool, interface{}) {
+	s := state.(runnerState)
+	call, ok := op.Call.(RunnerCall)
+	if !ok {
+		return false, state
+	}
+
+	if call.Op == "delete" {
+		if op.Err != nil {
+			return true, s
+		}
+		next := cloneRunnerState(s)
+		delete(next, call.BotID)
+		return true, next
+	}
+
+	ret, ok := op.Return.(RunnerReturn)
+	if !ok {
+		// Calls that don't report a status transition (e.g. a failed start)
+		// don't move the state machine.
+		return op.Err != nil, s
+	}
+	if op.Err != nil {
+		return true, s
+	}
+
+	current, existed := s[call.BotID]
+	if !existed {
+		if call.Op != "create" {
+			return false, state // can't start/stop/restart a bot that was never created
+		}
+	} else if current != ret.Status {
+		if !runnerTransitionAllowed(current, ret.Status) {
+			return false, state
+		}
+	}
+
+	next := cloneRunnerState(s)
+	next[call.BotID] = ret.Status
+	return true, next
+}
+
+func runnerTransitionAllowed(from, to enum.BotStatus) bool {
+	for _, allowed := range runnerLifecycleEdges[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneRunnerState(s runnerState) runnerState {
+	next := make(runnerState, len(s))
+	for k, v := range s {
+		next[k] = v
+	}
+	return next
+}
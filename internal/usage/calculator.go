@@ -2,6 +2,7 @@ package usage
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -256,6 +257,25 @@ func (c *calculator) GetRunnerRates(ctx context.Context, runnerID uuid.UUID) (*R
 	return rates, nil
 }
 
+// GetRunnerPricingPlan retrieves the tiered PricingPlan configured for a
+// runner, or nil if the runner has no pricing_plan set.
+func (c *calculator) GetRunnerPricingPlan(ctx context.Context, runnerID uuid.UUID) (*PricingPlan, error) {
+	runner, err := c.client.BotRunner.Get(ctx, runnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if runner.PricingPlan == nil || *runner.PricingPlan == "" {
+		return nil, nil
+	}
+
+	plan, err := LoadPricingPlan([]byte(*runner.PricingPlan))
+	if err != nil {
+		return nil, fmt.Errorf("runner %s: %w", runnerID, err)
+	}
+	return plan, nil
+}
+
 // CombineAggregationsToEntity merges multiple aggregation records into a single synthetic entity.
 // This is used by GraphQL resolvers to return a combined ResourceUsageAggregation for time ranges.
 func CombineAggregationsToEntity(aggs []*ent.ResourceUsageAggregation, start, end time.Time) *ent.ResourceUsageAggregation {
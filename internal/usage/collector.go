@@ -8,15 +8,18 @@ import (
 
 // collector implements the Collector interface.
 type collector struct {
-	client *ent.Client
+	client   *ent.Client
+	exporter *PrometheusExporter
 }
 
-// NewCollector creates a new usage collector.
-func NewCollector(client *ent.Client) Collector {
-	return &collector{client: client}
+// NewCollector creates a new usage collector. exporter may be nil, in which
+// case samples are persisted but not counted toward any live metric.
+func NewCollector(client *ent.Client, exporter *PrometheusExporter) Collector {
+	return &collector{client: client, exporter: exporter}
 }
 
-// RecordSample records a single usage sample to the database.
+// RecordSample records a single usage sample to the database and, if an
+// exporter is configured, folds it into the live Prometheus counters.
 func (c *collector) RecordSample(ctx context.Context, sample UsageSample) error {
 	_, err := c.client.ResourceUsageSample.Create().
 		SetResourceType(sample.ResourceType).
@@ -31,6 +34,12 @@ func (c *collector) RecordSample(ctx context.Context, sample UsageSample) error
 		SetBlockWriteBytes(sample.BlockWriteBytes).
 		SetSampledAt(sample.SampledAt).
 		Save(ctx)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if c.exporter != nil {
+		c.exporter.RecordSample(sample)
+	}
+	return nil
 }
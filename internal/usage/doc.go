@@ -135,7 +135,7 @@ If a user has resources on multiple runners, aggregate costs per runner first.
 
 ## Recording Samples (in monitors)
 
-	collector := usage.NewCollector(entClient)
+	collector := usage.NewCollector(entClient, nil)
 
 	// Only if billing enabled
 	if runner.BillingEnabled {
@@ -168,6 +168,26 @@ If a user has resources on multiple runners, aggregate costs per runner first.
 		log.Printf("sample cleanup failed: %v", err)
 	}
 
+## Running With Multiple Control-Plane Replicas
+
+When more than one control-plane replica runs the aggregation worker, wrap
+the Aggregator in a LeaderElectedAggregator so only the lease holder
+actually aggregates:
+
+	store := usage.NewSQLLeaseStore(db, "postgres")
+	leader := usage.NewLeaderElectedAggregator(
+		usage.NewAggregator(entClient), store, "usage-aggregator", hostname, usage.DefaultLeaseTTL,
+	)
+	leader.Start(ctx)
+	defer leader.Stop()
+
+	worker := monitor.NewUsageAggregatorWorker(entClient)
+	worker.SetAggregator(leader)
+
+Aggregation methods return usage.ErrLeaseLost when this replica isn't (or
+is no longer) the leader, so the worker's tick is a no-op instead of
+double-counting samples already claimed by another replica.
+
 ## Calculating Costs (for billing)
 
 	calculator := usage.NewCalculator(entClient)
@@ -178,6 +198,34 @@ If a user has resources on multiple runners, aggregate costs per runner first.
 	// Calculate cost for a specific runner
 	cost, err := calculator.CalculateCost(summary, rates)
 
+## Tiered/Committed-Use Pricing
+
+CalculateCost's flat per-unit rates are enough for simple runner billing, but
+org-level plans with volume discounts need PricingEngine instead:
+
+	plan, err := usage.LoadPricingPlan(planJSON)
+	engine := usage.NewPricingEngine()
+	priced := engine.Calculate(summary, plan)
+
+	// priced.TotalCost includes free-tier waivers, committed-use and
+	// sustained-use discounts; priced.CPUTiers etc. give the per-band
+	// breakdown for itemized invoices.
+
+## Live Prometheus Metrics
+
+PrometheusExporter keeps running usage counters in memory, fed inline from
+Collector.RecordSample, and serves them in Prometheus text exposition format:
+
+	exporter := usage.NewPrometheusExporter()
+	if err := exporter.LoadFromAggregations(ctx, entClient); err != nil {
+		log.Printf("failed to seed usage counters: %v", err)
+	}
+	collector := usage.NewCollector(entClient, exporter)
+	mux.Handle("/metrics", exporter)
+
+LoadFromAggregations reseeds the counters from the hourly aggregation table on
+startup, so a process restart doesn't reset a counter-based metric to zero.
+
 # Error Handling
 
 Sample collection errors should not fail status checks:
@@ -196,11 +244,16 @@ Aggregation failures are logged but don't block:
 
 # Files
 
-	doc.go          - Package documentation
-	types.go        - Domain types and interfaces
-	collector.go    - Sample recording logic
-	aggregator.go   - Hourly/daily aggregation
-	calculator.go   - Usage queries and cost calculation
+	doc.go               - Package documentation
+	types.go             - Domain types and interfaces
+	collector.go         - Sample recording logic
+	aggregator.go        - Hourly/daily aggregation
+	calculator.go        - Usage queries and cost calculation
+	pricing.go           - Tiered/committed-use/sustained-use pricing engine
+	metrics.go           - Live Prometheus counters and text exposition
+	leader.go            - LeaderElectedAggregator and the LeaseStore interface
+	lease_sql.go         - Postgres/SQLite sql_leases-backed LeaseStore
+	lease_kubernetes.go  - coordination.k8s.io/v1 Lease-backed LeaseStore
 
 # Related Packages
 
@@ -0,0 +1,206 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLeaseLost is returned by a LeaderElectedAggregator's methods when the
+// caller is not (or is no longer) the lease holder, so partial batches can
+// be aborted instead of double-counting usage across replicas.
+var ErrLeaseLost = fmt.Errorf("usage: lease lost or not held")
+
+// leaseRenewFraction controls how often a held lease is renewed, expressed
+// as a fraction of its TTL (renew at ttl/leaseRenewFraction).
+const leaseRenewFraction = 3
+
+// DefaultLeaseTTL is the default time a lease is valid for before it must be
+// renewed or is considered expired and eligible for takeover.
+const DefaultLeaseTTL = 30 * time.Second
+
+// LeaseStore backs distributed leader election for the usage aggregator.
+// Implementations must make TryAcquire/Renew/Release safe for concurrent
+// use by independent processes racing for the same lease name.
+type LeaseStore interface {
+	// TryAcquire attempts to take the named lease for holder, succeeding if
+	// the lease is unheld, already expired, or already held by holder. It
+	// reports whether the lease is now held by holder.
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// Renew extends a lease already held by holder. It reports false
+	// (without error) if holder no longer holds the lease, e.g. because it
+	// expired and was taken over by another process.
+	Renew(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// Release gives up a lease held by holder. It is a no-op if holder does
+	// not currently hold the lease.
+	Release(ctx context.Context, name, holder string) error
+}
+
+// LeaderElectedAggregator wraps an Aggregator so that AggregateHourly,
+// AggregateDaily, and CleanupOldSamples only run while this instance holds
+// a distributed lease, preventing multiple control-plane replicas from
+// double-counting or racing on the same usage data. Start a background
+// renewal loop with Start, and give it up cleanly with Stop.
+type LeaderElectedAggregator struct {
+	underlying Aggregator
+	store      LeaseStore
+	leaseName  string
+	holder     string
+	ttl        time.Duration
+
+	mu       sync.RWMutex
+	isLeader bool
+
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewLeaderElectedAggregator returns a LeaderElectedAggregator that guards
+// underlying with a lease named leaseName in store, identifying itself as
+// holder (typically a hostname or pod name). ttl of zero uses
+// DefaultLeaseTTL.
+func NewLeaderElectedAggregator(underlying Aggregator, store LeaseStore, leaseName, holder string, ttl time.Duration) *LeaderElectedAggregator {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+
+	return &LeaderElectedAggregator{
+		underlying: underlying,
+		store:      store,
+		leaseName:  leaseName,
+		holder:     holder,
+		ttl:        ttl,
+		stopChan:   make(chan struct{}),
+		doneChan:   make(chan struct{}),
+	}
+}
+
+// Start begins trying to acquire and renew the lease in the background.
+// Call Stop to release the lease and stop the loop.
+func (l *LeaderElectedAggregator) Start(ctx context.Context) {
+	go l.electionLoop(ctx)
+}
+
+// Stop releases the lease (if held) and stops the renewal loop, blocking
+// until it has exited.
+func (l *LeaderElectedAggregator) Stop() {
+	close(l.stopChan)
+	<-l.doneChan
+}
+
+// IsLeader reports whether this instance currently holds the lease.
+func (l *LeaderElectedAggregator) IsLeader() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.isLeader
+}
+
+func (l *LeaderElectedAggregator) setLeader(held bool) {
+	l.mu.Lock()
+	l.isLeader = held
+	l.mu.Unlock()
+}
+
+func (l *LeaderElectedAggregator) electionLoop(ctx context.Context) {
+	defer close(l.doneChan)
+
+	ticker := time.NewTicker(l.ttl / leaseRenewFraction)
+	defer ticker.Stop()
+
+	l.tryAcquireOrRenew(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.release()
+			return
+		case <-l.stopChan:
+			l.release()
+			return
+		case <-ticker.C:
+			l.tryAcquireOrRenew(ctx)
+		}
+	}
+}
+
+func (l *LeaderElectedAggregator) tryAcquireOrRenew(ctx context.Context) {
+	var (
+		held bool
+		err  error
+	)
+
+	if l.IsLeader() {
+		held, err = l.store.Renew(ctx, l.leaseName, l.holder, l.ttl)
+	} else {
+		held, err = l.store.TryAcquire(ctx, l.leaseName, l.holder, l.ttl)
+	}
+
+	if err != nil {
+		// Leave the current leadership state as-is; a transient store error
+		// shouldn't immediately demote a healthy leader, and a non-leader
+		// simply retries next tick.
+		return
+	}
+
+	l.setLeader(held)
+}
+
+func (l *LeaderElectedAggregator) release() {
+	if !l.IsLeader() {
+		return
+	}
+	_ = l.store.Release(context.Background(), l.leaseName, l.holder)
+	l.setLeader(false)
+}
+
+// AggregateHourly runs the underlying Aggregator's AggregateHourly only
+// while this instance holds the lease, returning ErrLeaseLost otherwise.
+func (l *LeaderElectedAggregator) AggregateHourly(ctx context.Context, hour time.Time) error {
+	if !l.IsLeader() {
+		return ErrLeaseLost
+	}
+	if err := l.underlying.AggregateHourly(ctx, hour); err != nil {
+		return err
+	}
+	if !l.IsLeader() {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// AggregateDaily runs the underlying Aggregator's AggregateDaily only while
+// this instance holds the lease, returning ErrLeaseLost otherwise.
+func (l *LeaderElectedAggregator) AggregateDaily(ctx context.Context, day time.Time) error {
+	if !l.IsLeader() {
+		return ErrLeaseLost
+	}
+	if err := l.underlying.AggregateDaily(ctx, day); err != nil {
+		return err
+	}
+	if !l.IsLeader() {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// CleanupOldSamples runs the underlying Aggregator's CleanupOldSamples only
+// while this instance holds the lease, returning ErrLeaseLost otherwise.
+func (l *LeaderElectedAggregator) CleanupOldSamples(ctx context.Context, olderThan time.Duration) (int, error) {
+	if !l.IsLeader() {
+		return 0, ErrLeaseLost
+	}
+	deleted, err := l.underlying.CleanupOldSamples(ctx, olderThan)
+	if err != nil {
+		return deleted, err
+	}
+	if !l.IsLeader() {
+		return deleted, ErrLeaseLost
+	}
+	return deleted, nil
+}
+
+// Compile-time interface compliance check.
+var _ Aggregator = (*LeaderElectedAggregator)(nil)
@@ -0,0 +1,144 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// countingAggregator counts AggregateHourly invocations so the test can
+// assert exactly one of two leader-elected instances ran per tick.
+type countingAggregator struct {
+	calls int64
+}
+
+func (c *countingAggregator) AggregateHourly(ctx context.Context, hour time.Time) error {
+	atomic.AddInt64(&c.calls, 1)
+	return nil
+}
+
+func (c *countingAggregator) AggregateDaily(ctx context.Context, day time.Time) error {
+	return nil
+}
+
+func (c *countingAggregator) CleanupOldSamples(ctx context.Context, olderThan time.Duration) (int, error) {
+	return 0, nil
+}
+
+// TestLeaderElectedAggregator_OnlyOneRunsPerTick spins up two
+// LeaderElectedAggregator instances sharing one in-memory sqlite
+// sql_leases table and asserts that, at any point in time, only one of
+// them believes it is the leader and able to run AggregateHourly.
+func TestLeaderElectedAggregator_OnlyOneRunsPerTick(t *testing.T) {
+	// A shared in-memory sqlite database (not ":memory:", which gives each
+	// connection its own database) so both stores see the same table.
+	db, err := sql.Open("sqlite3", "file:leader_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) // shared in-memory sqlite serializes better with one connection
+
+	store := NewSQLLeaseStore(db, "sqlite3")
+	if err := store.EnsureSchema(context.Background()); err != nil {
+		t.Fatalf("EnsureSchema() error = %v", err)
+	}
+
+	const ttl = 150 * time.Millisecond
+
+	underlyingA := &countingAggregator{}
+	underlyingB := &countingAggregator{}
+
+	a := NewLeaderElectedAggregator(underlyingA, store, "usage-aggregator", "replica-a", ttl)
+	b := NewLeaderElectedAggregator(underlyingB, store, "usage-aggregator", "replica-b", ttl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a.Start(ctx)
+	b.Start(ctx)
+	defer a.Stop()
+	defer b.Stop()
+
+	// Give the election loops time to settle on a single leader.
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if a.IsLeader() != b.IsLeader() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if a.IsLeader() == b.IsLeader() {
+		t.Fatalf("expected exactly one leader, got a.IsLeader()=%v b.IsLeader()=%v", a.IsLeader(), b.IsLeader())
+	}
+
+	var wg sync.WaitGroup
+	for _, agg := range []*LeaderElectedAggregator{a, b} {
+		wg.Add(1)
+		go func(agg *LeaderElectedAggregator) {
+			defer wg.Done()
+			_ = agg.AggregateHourly(ctx, time.Now())
+		}(agg)
+	}
+	wg.Wait()
+
+	totalCalls := atomic.LoadInt64(&underlyingA.calls) + atomic.LoadInt64(&underlyingB.calls)
+	if totalCalls != 1 {
+		t.Errorf("expected exactly 1 underlying AggregateHourly call across both replicas, got %d", totalCalls)
+	}
+
+	nonLeader := a
+	if a.IsLeader() {
+		nonLeader = b
+	}
+	if err := nonLeader.AggregateHourly(ctx, time.Now()); err != ErrLeaseLost {
+		t.Errorf("non-leader AggregateHourly() error = %v, want ErrLeaseLost", err)
+	}
+}
+
+// TestSQLLeaseStore_TakeOverAfterExpiry verifies that once a lease's TTL
+// elapses without renewal, a different holder can acquire it.
+func TestSQLLeaseStore_TakeOverAfterExpiry(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:leader_takeover_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	store := NewSQLLeaseStore(db, "sqlite3")
+	ctx := context.Background()
+	if err := store.EnsureSchema(ctx); err != nil {
+		t.Fatalf("EnsureSchema() error = %v", err)
+	}
+
+	const ttl = 20 * time.Millisecond
+
+	held, err := store.TryAcquire(ctx, "lease", "holder-a", ttl)
+	if err != nil || !held {
+		t.Fatalf("TryAcquire(holder-a) = %v, %v, want true, nil", held, err)
+	}
+
+	held, err = store.TryAcquire(ctx, "lease", "holder-b", ttl)
+	if err != nil || held {
+		t.Fatalf("TryAcquire(holder-b) while holder-a's lease is live = %v, %v, want false, nil", held, err)
+	}
+
+	time.Sleep(3 * ttl)
+
+	held, err = store.TryAcquire(ctx, "lease", "holder-b", ttl)
+	if err != nil || !held {
+		t.Fatalf("TryAcquire(holder-b) after expiry = %v, %v, want true, nil", held, err)
+	}
+
+	renewed, err := store.Renew(ctx, "lease", "holder-a", ttl)
+	if err != nil || renewed {
+		t.Errorf("Renew(holder-a) after takeover = %v, %v, want false, nil", renewed, err)
+	}
+}
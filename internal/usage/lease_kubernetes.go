@@ -0,0 +1,147 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// K8sLeaseStore implements LeaseStore on top of coordination.k8s.io/v1
+// Lease objects, for control planes that run under Kubernetes and would
+// rather piggyback on its lease API than manage a sql_leases table.
+type K8sLeaseStore struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewK8sLeaseStore returns a K8sLeaseStore that manages Lease objects in
+// namespace via client.
+func NewK8sLeaseStore(client kubernetes.Interface, namespace string) *K8sLeaseStore {
+	return &K8sLeaseStore{client: client, namespace: namespace}
+}
+
+// TryAcquire implements LeaseStore.
+func (k *K8sLeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := metav1.NewMicroTime(time.Now().UTC())
+	durationSeconds := int32(ttl.Seconds())
+
+	existing, err := k.client.CoordinationV1().Leases(k.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: k.namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if _, err := k.client.CoordinationV1().Leases(k.namespace).Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Lost the create race to another process.
+				return false, nil
+			}
+			return false, fmt.Errorf("usage: create lease %q: %w", name, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("usage: get lease %q: %w", name, err)
+	}
+
+	if existing.Spec.HolderIdentity != nil && *existing.Spec.HolderIdentity == holder {
+		// Already held by us; treat as a successful (idempotent) acquire.
+		existing.Spec.RenewTime = &now
+		existing.Spec.LeaseDurationSeconds = &durationSeconds
+		if _, err := k.client.CoordinationV1().Leases(k.namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return false, fmt.Errorf("usage: refresh held lease %q: %w", name, err)
+		}
+		return true, nil
+	}
+
+	if !leaseExpired(existing, now.Time) {
+		return false, nil
+	}
+
+	existing.Spec.HolderIdentity = &holder
+	existing.Spec.LeaseDurationSeconds = &durationSeconds
+	existing.Spec.AcquireTime = &now
+	existing.Spec.RenewTime = &now
+
+	if _, err := k.client.CoordinationV1().Leases(k.namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			// Another process took it over first.
+			return false, nil
+		}
+		return false, fmt.Errorf("usage: take over lease %q: %w", name, err)
+	}
+	return true, nil
+}
+
+// Renew implements LeaseStore.
+func (k *K8sLeaseStore) Renew(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	existing, err := k.client.CoordinationV1().Leases(k.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("usage: get lease %q: %w", name, err)
+	}
+
+	if existing.Spec.HolderIdentity == nil || *existing.Spec.HolderIdentity != holder {
+		return false, nil
+	}
+
+	now := metav1.NewMicroTime(time.Now().UTC())
+	durationSeconds := int32(ttl.Seconds())
+	existing.Spec.RenewTime = &now
+	existing.Spec.LeaseDurationSeconds = &durationSeconds
+
+	if _, err := k.client.CoordinationV1().Leases(k.namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("usage: renew lease %q: %w", name, err)
+	}
+	return true, nil
+}
+
+// Release implements LeaseStore.
+func (k *K8sLeaseStore) Release(ctx context.Context, name, holder string) error {
+	existing, err := k.client.CoordinationV1().Leases(k.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("usage: get lease %q: %w", name, err)
+	}
+
+	if existing.Spec.HolderIdentity == nil || *existing.Spec.HolderIdentity != holder {
+		return nil
+	}
+
+	if err := k.client.CoordinationV1().Leases(k.namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("usage: release lease %q: %w", name, err)
+	}
+	return nil
+}
+
+// leaseExpired reports whether lease's renew window has elapsed as of now.
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+// Compile-time interface compliance check.
+var _ LeaseStore = (*K8sLeaseStore)(nil)
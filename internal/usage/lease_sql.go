@@ -0,0 +1,125 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLLeaseStore implements LeaseStore against a sql_leases table, supporting
+// both the "postgres" and "sqlite3" driver names used elsewhere in this
+// codebase (see cmd/server/main.go's parseDatabase). Acquisition is a single
+// upsert guarded by a WHERE clause on the stored renew_at, so two processes
+// racing to take the same lease can never both succeed.
+type SQLLeaseStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// NewSQLLeaseStore returns a SQLLeaseStore using db, with SQL rendered for
+// dialect ("postgres" or "sqlite3").
+func NewSQLLeaseStore(db *sql.DB, dialect string) *SQLLeaseStore {
+	return &SQLLeaseStore{db: db, dialect: dialect}
+}
+
+// Compile-time interface compliance check.
+var _ LeaseStore = (*SQLLeaseStore)(nil)
+
+// EnsureSchema creates the sql_leases table if it does not already exist.
+func (s *SQLLeaseStore) EnsureSchema(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS sql_leases (
+	name        TEXT PRIMARY KEY,
+	holder      TEXT NOT NULL,
+	acquired_at TIMESTAMP NOT NULL,
+	renew_at    TIMESTAMP NOT NULL,
+	ttl_seconds BIGINT NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("usage: create sql_leases table: %w", err)
+	}
+	return nil
+}
+
+// TryAcquire implements LeaseStore.
+func (s *SQLLeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	renewAt := now.Add(ttl)
+
+	query := s.render(`
+INSERT INTO sql_leases (name, holder, acquired_at, renew_at, ttl_seconds)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET
+	holder      = excluded.holder,
+	acquired_at = excluded.acquired_at,
+	renew_at    = excluded.renew_at,
+	ttl_seconds = excluded.ttl_seconds
+WHERE sql_leases.renew_at < ? OR sql_leases.holder = ?`)
+
+	result, err := s.db.ExecContext(ctx, query, name, holder, now, renewAt, int64(ttl.Seconds()), now, holder)
+	if err != nil {
+		return false, fmt.Errorf("usage: acquire lease %q: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("usage: acquire lease %q: %w", name, err)
+	}
+	return affected > 0, nil
+}
+
+// Renew implements LeaseStore.
+func (s *SQLLeaseStore) Renew(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	renewAt := now.Add(ttl)
+
+	query := s.render(`
+UPDATE sql_leases SET renew_at = ?, ttl_seconds = ?
+WHERE name = ? AND holder = ?`)
+
+	result, err := s.db.ExecContext(ctx, query, renewAt, int64(ttl.Seconds()), name, holder)
+	if err != nil {
+		return false, fmt.Errorf("usage: renew lease %q: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("usage: renew lease %q: %w", name, err)
+	}
+	return affected > 0, nil
+}
+
+// Release implements LeaseStore.
+func (s *SQLLeaseStore) Release(ctx context.Context, name, holder string) error {
+	query := s.render(`DELETE FROM sql_leases WHERE name = ? AND holder = ?`)
+
+	if _, err := s.db.ExecContext(ctx, query, name, holder); err != nil {
+		return fmt.Errorf("usage: release lease %q: %w", name, err)
+	}
+	return nil
+}
+
+// render rewrites a query written with "?" placeholders into the dialect's
+// native placeholder style. sqlite3 (and the default) uses "?" as-is;
+// postgres uses "$1", "$2", ...
+func (s *SQLLeaseStore) render(query string) string {
+	if s.dialect != "postgres" {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
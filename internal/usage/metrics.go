@@ -0,0 +1,147 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"volaticloud/internal/ent"
+	"volaticloud/internal/ent/resourceusageaggregation"
+	"volaticloud/internal/enum"
+)
+
+// metricLabels identifies one label set the exporter tracks a running
+// counter for.
+type metricLabels struct {
+	OwnerID      string
+	ResourceType enum.ResourceType
+	RunnerID     string
+}
+
+// metricTotals holds the cumulative totals for one label set.
+type metricTotals struct {
+	CPUCoreSeconds  float64
+	MemoryGBSeconds float64
+	NetworkBytes    float64
+}
+
+// PrometheusExporter maintains running usage counters, labeled by owner,
+// resource type, and runner, and serves them in Prometheus text exposition
+// format. Counters are incremented inline as samples are recorded (see
+// Collector.RecordSample) so operators can scrape live billing metrics
+// without hitting the raw samples table. LoadFromAggregations reseeds the
+// counters from the hourly aggregation table, so a process restart doesn't
+// reset them to zero and trigger a spurious counter-reset alert downstream.
+type PrometheusExporter struct {
+	mu     sync.Mutex
+	totals map[metricLabels]*metricTotals
+}
+
+// NewPrometheusExporter creates an empty exporter. Call LoadFromAggregations
+// once at startup to seed it with historical totals before serving traffic.
+func NewPrometheusExporter() *PrometheusExporter {
+	return &PrometheusExporter{totals: make(map[metricLabels]*metricTotals)}
+}
+
+// RecordSample folds one usage sample's contribution into the running
+// counters. Called inline from Collector.RecordSample.
+func (e *PrometheusExporter) RecordSample(sample UsageSample) {
+	labels := metricLabels{
+		OwnerID:      sample.OwnerID,
+		ResourceType: sample.ResourceType,
+		RunnerID:     sample.RunnerID.String(),
+	}
+	cpuCoreSeconds := (sample.CPUPercent / 100) * SampleIntervalSeconds
+	memoryGBSeconds := (float64(sample.MemoryBytes) / BytesPerGB) * SampleIntervalSeconds
+	networkBytes := float64(sample.NetworkRxBytes + sample.NetworkTxBytes)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t := e.totalsFor(labels)
+	t.CPUCoreSeconds += cpuCoreSeconds
+	t.MemoryGBSeconds += memoryGBSeconds
+	t.NetworkBytes += networkBytes
+}
+
+// totalsFor returns the metricTotals for labels, creating it if needed.
+// Callers must hold e.mu.
+func (e *PrometheusExporter) totalsFor(labels metricLabels) *metricTotals {
+	t, ok := e.totals[labels]
+	if !ok {
+		t = &metricTotals{}
+		e.totals[labels] = t
+	}
+	return t
+}
+
+// LoadFromAggregations seeds the exporter's counters from every persisted
+// hourly aggregation row, replacing whatever totals it held before.
+func (e *PrometheusExporter) LoadFromAggregations(ctx context.Context, client *ent.Client) error {
+	aggs, err := client.ResourceUsageAggregation.Query().
+		Where(resourceusageaggregation.GranularityEQ(enum.AggregationGranularityHourly)).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("usage: load prometheus counters: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.totals = make(map[metricLabels]*metricTotals, len(aggs))
+	for _, agg := range aggs {
+		labels := metricLabels{
+			OwnerID:      agg.OwnerID,
+			ResourceType: agg.ResourceType,
+			RunnerID:     agg.RunnerID.String(),
+		}
+		t := e.totalsFor(labels)
+		t.CPUCoreSeconds += agg.CPUCoreSeconds
+		t.MemoryGBSeconds += agg.MemoryGBSeconds
+		t.NetworkBytes += float64(agg.NetworkRxBytes + agg.NetworkTxBytes)
+	}
+	return nil
+}
+
+// ServeHTTP writes every counter in Prometheus text exposition format, so
+// the exporter can be mounted directly as an http.Handler (e.g. at /metrics).
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	keys := make([]metricLabels, 0, len(e.totals))
+	totals := make(map[metricLabels]metricTotals, len(e.totals))
+	for k, t := range e.totals {
+		keys = append(keys, k)
+		totals[k] = *t
+	}
+	e.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].OwnerID != keys[j].OwnerID {
+			return keys[i].OwnerID < keys[j].OwnerID
+		}
+		if keys[i].ResourceType != keys[j].ResourceType {
+			return keys[i].ResourceType < keys[j].ResourceType
+		}
+		return keys[i].RunnerID < keys[j].RunnerID
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	writeMetric(w, "volaticloud_usage_cpu_core_seconds_total", "Cumulative CPU core-seconds consumed.", keys, func(k metricLabels) float64 {
+		return totals[k].CPUCoreSeconds
+	})
+	writeMetric(w, "volaticloud_usage_memory_gb_seconds_total", "Cumulative memory GB-seconds consumed.", keys, func(k metricLabels) float64 {
+		return totals[k].MemoryGBSeconds
+	})
+	writeMetric(w, "volaticloud_usage_network_bytes_total", "Cumulative network bytes transferred (rx+tx).", keys, func(k metricLabels) float64 {
+		return totals[k].NetworkBytes
+	})
+}
+
+func writeMetric(w http.ResponseWriter, name, help string, keys []metricLabels, value func(metricLabels) float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{owner_id=%q,resource_type=%q,runner_id=%q} %g\n", name, k.OwnerID, string(k.ResourceType), k.RunnerID, value(k))
+	}
+}
@@ -0,0 +1,271 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PricingTier is one band of a tiered rate: it covers the next UpToUnits of
+// usage (cumulative across the plan's tiers, not per-tier) at PricePerUnit.
+// A tier with UpToUnits == 0 is the remainder tier and must be last.
+type PricingTier struct {
+	UpToUnits    float64 `json:"upToUnits"`
+	PricePerUnit float64 `json:"pricePerUnit"`
+}
+
+// CommittedUsePlan prices the first CommittedCoreHours of CPU usage per
+// billing period at DiscountedPricePerCoreHour; usage beyond the commitment
+// falls through to CPUTiers.
+type CommittedUsePlan struct {
+	CommittedCoreHours         float64 `json:"committedCoreHours"`
+	DiscountedPricePerCoreHour float64 `json:"discountedPricePerCoreHour"`
+}
+
+// SustainedUseDiscount discounts CPU and memory cost once a resource ran for
+// more than MinUsageRatio of the full billing period, scaling linearly up to
+// MaxDiscountPercent at 100% usage (mirrors GCP's sustained-use discount).
+type SustainedUseDiscount struct {
+	MinUsageRatio      float64 `json:"minUsageRatio"`
+	MaxDiscountPercent float64 `json:"maxDiscountPercent"`
+}
+
+// PricingPlan is a loadable (JSON) document describing how to price one
+// org's usage. It replaces RunnerRates' flat per-unit multiplication with
+// tiered bands, an optional committed-use baseline, an optional
+// sustained-use auto-discount, and per-org free tiers.
+//
+// Tiers are evaluated cumulatively: given CPUTiers
+// [{UpToUnits: 100, Price: X}, {UpToUnits: 500, Price: Y}, {UpToUnits: 0, Price: Z}],
+// the first 100 core-hours cost X/hr, the next 400 (up to 500 total) cost
+// Y/hr, and everything beyond 500 costs Z/hr.
+type PricingPlan struct {
+	Name string `json:"name"`
+
+	CPUTiers     []PricingTier `json:"cpuTiers,omitempty"`
+	MemoryTiers  []PricingTier `json:"memoryTiers,omitempty"`
+	NetworkTiers []PricingTier `json:"networkTiers,omitempty"`
+	StorageTiers []PricingTier `json:"storageTiers,omitempty"`
+
+	// Free tiers waive the first N units per billing period before any tier
+	// pricing applies.
+	FreeCoreHours float64 `json:"freeCoreHours,omitempty"`
+	FreeGBHours   float64 `json:"freeGBHours,omitempty"`
+	FreeNetworkGB float64 `json:"freeNetworkGB,omitempty"`
+	FreeStorageGB float64 `json:"freeStorageGB,omitempty"`
+
+	CommittedUse         *CommittedUsePlan     `json:"committedUse,omitempty"`
+	SustainedUseDiscount *SustainedUseDiscount `json:"sustainedUseDiscount,omitempty"`
+}
+
+// LoadPricingPlan parses a PricingPlan document. Only JSON is supported for
+// now; a YAML loader can be added once a YAML dependency is vendored, same
+// document shape either way (struct tags are JSON-only until then).
+func LoadPricingPlan(data []byte) (*PricingPlan, error) {
+	var plan PricingPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("usage: parse pricing plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// TierCost is one pricing tier's contribution to a cost line, so invoice
+// line-items can show the band breakdown (e.g. "first 100 core-hours @
+// $0.05", "next 400 @ $0.04").
+type TierCost struct {
+	Units        float64
+	PricePerUnit float64
+	Cost         float64
+}
+
+// PricedCost is CalculateCost's tiered-pricing counterpart: it reports the
+// same cost totals as UsageCost plus the per-tier breakdown and discounts
+// PricingEngine applied to reach them.
+type PricedCost struct {
+	UsageCost
+
+	CPUTiers     []TierCost
+	MemoryTiers  []TierCost
+	NetworkTiers []TierCost
+	StorageTiers []TierCost
+
+	FreeTierSavings     float64
+	CommittedUseSavings float64
+	SustainedUseSavings float64
+}
+
+// PricingEngine prices a UsageSummary against a PricingPlan.
+type PricingEngine struct{}
+
+// NewPricingEngine creates a PricingEngine. It holds no state; a plan is
+// supplied per call so the same engine can price many orgs' usage against
+// their individual plans.
+func NewPricingEngine() *PricingEngine {
+	return &PricingEngine{}
+}
+
+// Calculate prices summary against plan, applying free tiers first, then
+// committed-use and tiered rates for CPU, then tiered rates for
+// memory/network/storage, then the sustained-use discount over the whole
+// CPU+memory cost.
+func (e *PricingEngine) Calculate(summary *UsageSummary, plan *PricingPlan) *PricedCost {
+	if summary == nil || plan == nil {
+		return &PricedCost{UsageCost: UsageCost{Currency: "USD"}}
+	}
+
+	cpuCoreHours := summary.CPUCoreSeconds / SecondsPerHour
+	memoryGBHours := summary.MemoryGBSeconds / SecondsPerHour
+	networkGB := float64(summary.NetworkRxBytes+summary.NetworkTxBytes) / BytesPerGB
+	storageGB := float64(summary.BlockReadBytes+summary.BlockWriteBytes) / BytesPerGB
+
+	billableCoreHours, freeCoreSavingsUnits := applyFreeTier(cpuCoreHours, plan.FreeCoreHours)
+	billableGBHours, freeGBSavingsUnits := applyFreeTier(memoryGBHours, plan.FreeGBHours)
+	billableNetworkGB, freeNetworkSavingsUnits := applyFreeTier(networkGB, plan.FreeNetworkGB)
+	billableStorageGB, freeStorageSavingsUnits := applyFreeTier(storageGB, plan.FreeStorageGB)
+
+	result := &PricedCost{UsageCost: UsageCost{Currency: "USD"}}
+	result.FreeTierSavings = freeCoreSavingsUnits + freeGBSavingsUnits + freeNetworkSavingsUnits + freeStorageSavingsUnits
+
+	var committedSavings float64
+	if plan.CommittedUse != nil && plan.CommittedUse.CommittedCoreHours > 0 {
+		committed := billableCoreHours
+		if committed > plan.CommittedUse.CommittedCoreHours {
+			committed = plan.CommittedUse.CommittedCoreHours
+		}
+		committedCost := committed * plan.CommittedUse.DiscountedPricePerCoreHour
+		result.CPUTiers = append(result.CPUTiers, TierCost{
+			Units:        committed,
+			PricePerUnit: plan.CommittedUse.DiscountedPricePerCoreHour,
+			Cost:         committedCost,
+		})
+		result.CPUCost += committedCost
+
+		tieredCost, tiers := priceTiersFromFloor(committed, billableCoreHours-committed, plan.CPUTiers)
+		result.CPUTiers = append(result.CPUTiers, tiers...)
+		result.CPUCost += tieredCost
+
+		// Had this usage not been committed, it would have cost the full
+		// tiered rate; the difference is the commitment's savings.
+		uncommittedCost, _ := priceTiers(committed, plan.CPUTiers)
+		committedSavings = uncommittedCost - committedCost
+	} else {
+		cost, tiers := priceTiers(billableCoreHours, plan.CPUTiers)
+		result.CPUCost = cost
+		result.CPUTiers = tiers
+	}
+	result.CommittedUseSavings = committedSavings
+
+	memoryCost, memoryTiers := priceTiers(billableGBHours, plan.MemoryTiers)
+	result.MemoryCost = memoryCost
+	result.MemoryTiers = memoryTiers
+
+	networkCost, networkTiers := priceTiers(billableNetworkGB, plan.NetworkTiers)
+	result.NetworkCost = networkCost
+	result.NetworkTiers = networkTiers
+
+	storageCost, storageTiers := priceTiers(billableStorageGB, plan.StorageTiers)
+	result.StorageCost = storageCost
+	result.StorageTiers = storageTiers
+
+	if plan.SustainedUseDiscount != nil {
+		discount := sustainedUseDiscountFor(summary, plan.SustainedUseDiscount)
+		if discount > 0 {
+			before := result.CPUCost + result.MemoryCost
+			result.CPUCost *= 1 - discount
+			result.MemoryCost *= 1 - discount
+			after := result.CPUCost + result.MemoryCost
+			result.SustainedUseSavings = before - after
+		}
+	}
+
+	result.TotalCost = result.CPUCost + result.MemoryCost + result.NetworkCost + result.StorageCost
+	return result
+}
+
+// applyFreeTier waives the first freeUnits of usage. It returns the
+// remaining billable usage and how many units (not dollars - the tiers
+// aren't known yet when free usage straddles a tier boundary) were waived.
+func applyFreeTier(units, freeUnits float64) (billable, waived float64) {
+	if freeUnits <= 0 || units <= 0 {
+		return units, 0
+	}
+	if units <= freeUnits {
+		return 0, units
+	}
+	return units - freeUnits, freeUnits
+}
+
+// priceTiers prices units against tiers cumulatively: tiers[0] covers the
+// first tiers[0].UpToUnits, tiers[1] covers the next
+// tiers[1].UpToUnits-tiers[0].UpToUnits, and so on. A tier with UpToUnits ==
+// 0 is the remainder tier and absorbs everything left over. If tiers is
+// empty, units are priced at zero (no rate configured).
+func priceTiers(units float64, tiers []PricingTier) (cost float64, breakdown []TierCost) {
+	return priceTiersFromFloor(0, units, tiers)
+}
+
+// priceTiersFromFloor is priceTiers, but resumes from startFloor units
+// already consumed instead of 0. This is what committed-use overage needs:
+// the committed hours already consumed the cheapest tiers up to
+// startFloor, so the remaining (billable - committed) hours must continue
+// from there rather than re-walking the tiers from the start, or usage that
+// crosses a tier boundary gets systematically undercharged.
+func priceTiersFromFloor(startFloor, units float64, tiers []PricingTier) (cost float64, breakdown []TierCost) {
+	if units <= 0 || len(tiers) == 0 {
+		return 0, nil
+	}
+
+	remaining := units
+	floor := startFloor
+	for _, tier := range tiers {
+		if remaining <= 0 {
+			break
+		}
+
+		capacity := tier.UpToUnits - floor
+		if tier.UpToUnits == 0 {
+			capacity = remaining // remainder tier: no upper bound
+		}
+		if capacity <= 0 {
+			continue // malformed/non-increasing tier boundary, skip
+		}
+
+		tierUnits := remaining
+		if tierUnits > capacity {
+			tierUnits = capacity
+		}
+
+		tierCost := tierUnits * tier.PricePerUnit
+		cost += tierCost
+		breakdown = append(breakdown, TierCost{Units: tierUnits, PricePerUnit: tier.PricePerUnit, Cost: tierCost})
+
+		remaining -= tierUnits
+		floor = tier.UpToUnits
+	}
+
+	return cost, breakdown
+}
+
+// sustainedUseDiscountFor computes the discount fraction from the ratio of
+// actual sampled runtime to the full billing period covered by summary,
+// scaled linearly from 0 at MinUsageRatio to MaxDiscountPercent at 100% use.
+func sustainedUseDiscountFor(summary *UsageSummary, discount *SustainedUseDiscount) float64 {
+	periodHours := summary.PeriodEnd.Sub(summary.PeriodStart).Hours()
+	if periodHours <= 0 {
+		return 0
+	}
+	actualHours := float64(summary.SampleCount*SampleIntervalSeconds) / SecondsPerHour
+	ratio := actualHours / periodHours
+	if ratio > 1 {
+		ratio = 1
+	}
+	if ratio <= discount.MinUsageRatio {
+		return 0
+	}
+
+	span := 1 - discount.MinUsageRatio
+	if span <= 0 {
+		return discount.MaxDiscountPercent / 100
+	}
+	scaled := (ratio - discount.MinUsageRatio) / span
+	return scaled * discount.MaxDiscountPercent / 100
+}
@@ -0,0 +1,68 @@
+package usage
+
+import "testing"
+
+func TestPriceTiers_CumulativeAcrossBoundary(t *testing.T) {
+	tiers := []PricingTier{
+		{UpToUnits: 100, PricePerUnit: 0.10},
+		{UpToUnits: 0, PricePerUnit: 0.05},
+	}
+
+	cost, breakdown := priceTiers(130, tiers)
+
+	want := 100*0.10 + 30*0.05
+	if cost != want {
+		t.Fatalf("cost = %v, want %v", cost, want)
+	}
+	if len(breakdown) != 2 {
+		t.Fatalf("breakdown length = %d, want 2", len(breakdown))
+	}
+}
+
+func TestPriceTiersFromFloor_ResumesPastCommittedUsage(t *testing.T) {
+	tiers := []PricingTier{
+		{UpToUnits: 100, PricePerUnit: 0.10},
+		{UpToUnits: 0, PricePerUnit: 0.05},
+	}
+
+	// 50 committed core-hours already consumed the first 50 units of the
+	// $0.10 tier; the remaining 80 billable hours must resume from there,
+	// not restart the tiers from zero.
+	cost, _ := priceTiersFromFloor(50, 80, tiers)
+
+	want := 50*0.10 + 30*0.05
+	if cost != want {
+		t.Fatalf("cost = %v, want %v", cost, want)
+	}
+}
+
+func TestPricingEngine_CalculateAccountsForCommittedUseFloor(t *testing.T) {
+	engine := NewPricingEngine()
+	summary := &UsageSummary{CPUCoreSeconds: 130 * SecondsPerHour}
+	plan := &PricingPlan{
+		CPUTiers: []PricingTier{
+			{UpToUnits: 100, PricePerUnit: 0.10},
+			{UpToUnits: 0, PricePerUnit: 0.05},
+		},
+		CommittedUse: &CommittedUsePlan{
+			CommittedCoreHours:         50,
+			DiscountedPricePerCoreHour: 0.08,
+		},
+	}
+
+	result := engine.Calculate(summary, plan)
+
+	// 50 committed hours @ $0.08 + (100-50) overage hours @ $0.10 + 30
+	// overage hours @ $0.05.
+	wantCPUCost := 50*0.08 + 50*0.10 + 30*0.05
+	if result.CPUCost != wantCPUCost {
+		t.Fatalf("CPUCost = %v, want %v", result.CPUCost, wantCPUCost)
+	}
+}
+
+func TestPricingEngine_CalculateNilInputs(t *testing.T) {
+	engine := NewPricingEngine()
+	if cost := engine.Calculate(nil, nil); cost.TotalCost != 0 {
+		t.Fatalf("TotalCost = %v, want 0", cost.TotalCost)
+	}
+}
@@ -102,6 +102,11 @@ type Calculator interface {
 
 	// GetRunnerRates retrieves pricing rates for a runner.
 	GetRunnerRates(ctx context.Context, runnerID uuid.UUID) (*RunnerRates, error)
+
+	// GetRunnerPricingPlan retrieves the tiered PricingPlan configured for a
+	// runner, or nil if it has none (billing should fall back to the flat
+	// rates from GetRunnerRates in that case).
+	GetRunnerPricingPlan(ctx context.Context, runnerID uuid.UUID) (*PricingPlan, error)
 }
 
 // Constants for billing calculations.